@@ -0,0 +1,65 @@
+package astitello
+
+// Activity thresholds
+var (
+	activityFlipAngle   = 60 // Pitch or roll (°) above which the drone is considered flipping
+	activityFlipDelta   = 45 // Pitch or roll change (°) between two states above which the drone is considered flipping
+	activityMovingSpeed = 1  // Speed (dm/s) on any axis above which the drone is considered moving
+	activityMovingYaw   = 3  // Yaw change (°) between two states above which the drone is considered moving
+)
+
+// Activities
+const (
+	ActivityFlipping Activity = "flipping"
+	ActivityGrounded Activity = "grounded"
+	ActivityHovering Activity = "hovering"
+	ActivityMoving   Activity = "moving"
+	ActivityUnknown  Activity = "unknown"
+)
+
+// Activity represents what the drone is doing, e.g. to display a label in a UI
+type Activity string
+
+// Activity returns what the drone is doing based on its latest states
+// It is a heuristic derived from the flight state, speeds and attitude changes rather than queried from the
+// drone: a sharp attitude change is reported as a flip and a quick rotation as a movement.
+func (d *Drone) Activity() Activity {
+	d.ms.Lock()
+	defer d.ms.Unlock()
+	return d.activity
+}
+
+func classifyActivity(previous, s State, hasPrevious bool, fs FlightState) Activity {
+	// Drone is on the ground
+	switch fs {
+	case FlightStateGrounded:
+		return ActivityGrounded
+	case FlightStateUnknown:
+		if s.Height == 0 {
+			return ActivityUnknown
+		}
+	}
+
+	// Drone is flipping
+	if abs(s.Attitude.Pitch) > activityFlipAngle || abs(s.Attitude.Roll) > activityFlipAngle ||
+		(hasPrevious && (abs(s.Attitude.Pitch-previous.Attitude.Pitch) > activityFlipDelta ||
+			abs(s.Attitude.Roll-previous.Attitude.Roll) > activityFlipDelta)) {
+		return ActivityFlipping
+	}
+
+	// Drone is moving
+	if abs(s.Speed.X) >= activityMovingSpeed || abs(s.Speed.Y) >= activityMovingSpeed || abs(s.Speed.Z) >= activityMovingSpeed ||
+		(hasPrevious && yawDelta(previous.Attitude.Yaw, s.Attitude.Yaw) > activityMovingYaw) {
+		return ActivityMoving
+	}
+	return ActivityHovering
+}
+
+// yawDelta returns the absolute yaw change, knowing yaw wraps around at ±180°
+func yawDelta(a, b int) int {
+	d := abs(b-a) % 360
+	if d > 180 {
+		d = 360 - d
+	}
+	return d
+}
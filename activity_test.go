@@ -0,0 +1,58 @@
+package astitello
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestClassifyActivity(t *testing.T) {
+	hovering := State{Attitude: Attitude{Pitch: 1, Roll: -1, Yaw: 90}, Height: 100}
+	for _, v := range []struct {
+		e           Activity
+		fs          FlightState
+		hasPrevious bool
+		name        string
+		previous    State
+		s           State
+	}{
+		{name: "grounded", e: ActivityGrounded, fs: FlightStateGrounded, s: State{Speed: Speed{X: 5}}},
+		{name: "unknown", e: ActivityUnknown, fs: FlightStateUnknown},
+		{name: "first state", e: ActivityHovering, fs: FlightStateUnknown, s: hovering},
+		{name: "hovering", e: ActivityHovering, fs: FlightStateAirborne, hasPrevious: true, previous: hovering, s: hovering},
+		{name: "moving", e: ActivityMoving, fs: FlightStateAirborne, hasPrevious: true, previous: hovering, s: State{Attitude: hovering.Attitude, Speed: Speed{Y: -3}}},
+		{name: "rotating", e: ActivityMoving, fs: FlightStateAirborne, hasPrevious: true, previous: hovering, s: State{Attitude: Attitude{Yaw: 100}}},
+		{name: "rotating across 180°", e: ActivityMoving, fs: FlightStateAirborne, hasPrevious: true, previous: State{Attitude: Attitude{Yaw: 178}}, s: State{Attitude: Attitude{Yaw: -175}}},
+		{name: "not rotating across 180°", e: ActivityHovering, fs: FlightStateAirborne, hasPrevious: true, previous: State{Attitude: Attitude{Yaw: 179}}, s: State{Attitude: Attitude{Yaw: -179}}},
+		{name: "flipping", e: ActivityFlipping, fs: FlightStateAirborne, s: State{Attitude: Attitude{Roll: 120}}},
+		{name: "starting to flip", e: ActivityFlipping, fs: FlightStateAirborne, hasPrevious: true, previous: hovering, s: State{Attitude: Attitude{Pitch: -50, Yaw: 90}}},
+	} {
+		if g := classifyActivity(v.previous, v.s, v.hasPrevious, v.fs); g != v.e {
+			t.Errorf("%s: expected %s, got %s", v.name, v.e, g)
+		}
+	}
+}
+
+func TestActivity(t *testing.T) {
+	// Set up and start
+	d, _, s, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// No state yet
+	if e, g := ActivityUnknown, d.Activity(); e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+
+	// Send state
+	if _, err := s.conn.Write([]byte(strState)); err != nil {
+		t.Fatal(fmt.Errorf("test: writing state failed: %w", err))
+	}
+	for n := time.Now(); d.Activity() == ActivityUnknown; time.Sleep(time.Millisecond) {
+		if time.Since(n) > time.Second {
+			t.Fatal("expected activity")
+		}
+	}
+	if e, g := ActivityMoving, d.Activity(); e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+}
@@ -0,0 +1,137 @@
+package astitello
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Altitude hold defaults
+var (
+	altitudeHoldInterval    = 100 * time.Millisecond // Interval at which the loop corrects the height
+	altitudeHoldMaxStateAge = 500 * time.Millisecond // Age above which the height is too old to be corrected, the drone sends a state every 100ms
+)
+
+// Minimum and maximum distances (cm) accepted by the up and down cmds
+const (
+	altitudeMinDistance = 20
+	altitudeMaxDistance = 500
+)
+
+// HoldAltitude keeps the drone at targetCm cm until the context is done, at which point sticks are set back to
+// their neutral position. It runs a proportional loop: the ud stick is set to the difference between the
+// target and the height reported by the latest state multiplied by the gain, and clamped. Other sticks are
+// kept neutral. Check out WithAltitudeHold to configure the gain and the clamp.
+// When MovementModeCommand is used, the up or down cmds are sent with the difference instead, which is ignored
+// when it is less than 20cm.
+// When no state has been received for 500ms, or at all, the height can't be trusted: sticks are set to their
+// neutral position instead until states are received again.
+func (d *Drone) HoldAltitude(ctx context.Context, targetCm int) (err error) {
+	// Create ticker
+	t := time.NewTicker(altitudeHoldInterval)
+	defer t.Stop()
+
+	// Loop
+	for {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			// Set neutral sticks
+			if err = d.Hover(); err != nil {
+				err = fmt.Errorf("astitello: setting neutral sticks failed: %w", err)
+				return
+			}
+			return
+		}
+
+		// Get height
+		d.ms.Lock()
+		h := d.s.Height
+		d.ms.Unlock()
+
+		// State is stale or no state has been received yet
+		if d.StateAge() > altitudeHoldMaxStateAge {
+			if err = d.Hover(); err != nil {
+				err = fmt.Errorf("astitello: setting neutral sticks failed: %w", err)
+				return
+			}
+			continue
+		}
+
+		// Correct altitude
+		if err = d.correctAltitude(ctx, targetCm-h); err != nil {
+			// The context is done while correcting
+			if ctx.Err() != nil {
+				err = nil
+				continue
+			}
+			return
+		}
+	}
+}
+
+func (d *Drone) correctAltitude(ctx context.Context, delta int) (err error) {
+	// Command mode
+	if d.o.movementMode == MovementModeCommand {
+		// Get distance
+		distance := altitudeDistance(delta)
+		if distance == 0 {
+			return
+		}
+
+		// Get cmd
+		name, dz := "up", distance
+		if delta < 0 {
+			name, dz = "down", -distance
+		}
+
+		// Check flight state
+		if err = d.checkAirborne(); err != nil {
+			return
+		}
+
+		// Send cmd, which stops waiting for the movement to be done once the context is done
+		if err = d.sendCmd(&cmd{
+			cmd:     fmt.Sprintf("%s %d", name, distance),
+			ctx:     ctx,
+			h:       defaultRespHandler,
+			timeout: d.o.timeouts.move(),
+		}); err != nil {
+			err = fmt.Errorf("astitello: sending %s cmd failed: %w", name, err)
+			return
+		}
+
+		// Update displacement
+		d.addMove(0, 0, dz)
+		return
+	}
+
+	// Set sticks
+	if err = d.SetSticks(0, 0, altitudeCorrection(delta, d.o.altitudeHoldGain, d.o.altitudeHoldMax), 0); err != nil {
+		err = fmt.Errorf("astitello: setting sticks failed: %w", err)
+		return
+	}
+	return
+}
+
+func altitudeCorrection(delta int, gain float64, max int) (ud int) {
+	ud = int(math.Round(float64(delta) * gain))
+	if ud > max {
+		ud = max
+	} else if ud < -max {
+		ud = -max
+	}
+	return
+}
+
+// altitudeDistance returns the distance the up or down cmds should be sent with to correct delta, or 0 if it is
+// too small
+func altitudeDistance(delta int) (distance int) {
+	if distance = abs(delta); distance < altitudeMinDistance {
+		return 0
+	} else if distance > altitudeMaxDistance {
+		distance = altitudeMaxDistance
+	}
+	return
+}
@@ -0,0 +1,148 @@
+package astitello
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAltitudeCorrection(t *testing.T) {
+	for _, v := range []struct {
+		delta int
+		e     int
+		gain  float64
+	}{
+		{delta: 10, e: 5, gain: 0.5},
+		{delta: -10, e: -5, gain: 0.5},
+		{delta: 0, e: 0, gain: 0.5},
+		{delta: 200, e: 50, gain: 1},
+		{delta: -200, e: -50, gain: 1},
+	} {
+		if g := altitudeCorrection(v.delta, v.gain, 50); g != v.e {
+			t.Errorf("expected %d, got %d", v.e, g)
+		}
+	}
+}
+
+func TestHoldAltitude(t *testing.T) {
+	// Update defaults
+	i := altitudeHoldInterval
+	altitudeHoldInterval = 5 * time.Millisecond
+	defer func() { altitudeHoldInterval = i }()
+
+	// Set up and start
+	d, c, s, _, teardown := setupAndStart(t, WithAltitudeHold(0.5, 30))
+	defer teardown()
+
+	// Hold altitude
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errs := make(chan error, 1)
+	go func() { errs <- d.HoldAltitude(ctx, 100) }()
+
+	// Corrections should follow the height
+	for _, v := range []struct {
+		e string
+		h int
+	}{
+		{e: "rc 0 0 10 0", h: 80},
+		{e: "rc 0 0 -30 0", h: 200},
+	} {
+		// Send state
+		if _, err := s.conn.Write([]byte(strings.Replace(strState, "h:17", fmt.Sprintf("h:%d", v.h), 1))); err != nil {
+			t.Fatal(fmt.Errorf("test: writing state failed: %w", err))
+		}
+
+		// Wait for correction
+		for n, found := time.Now(), false; !found; time.Sleep(time.Millisecond) {
+			for _, cmd := range c.received() {
+				if cmd == v.e {
+					found = true
+				}
+			}
+			if !found && time.Since(n) > time.Second {
+				t.Fatalf("expected %s", v.e)
+			}
+		}
+	}
+
+	// Neutral sticks should be sent once the context is done
+	cancel()
+	if err := <-errs; err != nil {
+		t.Error(fmt.Errorf("test: holding altitude failed: %w", err))
+	}
+	for n := time.Now(); ; time.Sleep(time.Millisecond) {
+		cmds := c.received()
+		if e, g := "rc 0 0 0 0", cmds[len(cmds)-1]; e == g {
+			break
+		} else if time.Since(n) > time.Second {
+			t.Fatalf("expected %s, got %s", e, g)
+		}
+	}
+}
+
+func TestHoldAltitudeStaleState(t *testing.T) {
+	// Update defaults
+	i, a := altitudeHoldInterval, altitudeHoldMaxStateAge
+	altitudeHoldInterval, altitudeHoldMaxStateAge = 5*time.Millisecond, 50*time.Millisecond
+	defer func() { altitudeHoldInterval, altitudeHoldMaxStateAge = i, a }()
+
+	// Set up and start
+	d, c, s, _, teardown := setupAndStart(t, WithAltitudeHold(0.5, 30))
+	defer teardown()
+
+	// Hold altitude
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errs := make(chan error, 1)
+	go func() { errs <- d.HoldAltitude(ctx, 100) }()
+
+	// Wait for cmd
+	wait := func(e string, from int) (idx int) {
+		for n := time.Now(); ; time.Sleep(time.Millisecond) {
+			cmds := c.received()
+			for idx = from; idx < len(cmds); idx++ {
+				if cmds[idx] == e {
+					return
+				}
+			}
+			if time.Since(n) > time.Second {
+				t.Fatalf("expected %s after %d, got %+v", e, from, cmds)
+			}
+		}
+	}
+
+	// Sticks should be neutral until a state is received
+	idx := wait("rc 0 0 0 0", 0)
+
+	// Height should be corrected while state is fresh
+	if _, err := s.conn.Write([]byte(strings.Replace(strState, "h:17", "h:80", 1))); err != nil {
+		t.Fatal(fmt.Errorf("test: writing state failed: %w", err))
+	}
+	idx = wait("rc 0 0 10 0", idx)
+
+	// Sticks should be neutral once state is stale, while the context is not done
+	wait("rc 0 0 0 0", idx)
+	select {
+	case err := <-errs:
+		t.Fatalf("expected hold to be running, got %v", err)
+	default:
+	}
+}
+
+func TestWithAltitudeHold(t *testing.T) {
+	for _, v := range []struct {
+		e   int
+		max int
+	}{
+		{e: 0, max: -10},
+		{e: 30, max: 30},
+		{e: 100, max: 200},
+	} {
+		if g := newOptions([]Option{WithAltitudeHold(1, v.max)}).altitudeHoldMax; g != v.e {
+			t.Errorf("%d: expected %d, got %d", v.max, v.e, g)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package astitello
+
+import "fmt"
+
+// Adaptive bitrate defaults
+var (
+	bitrateHighLoss     = 0.1  // Video loss above which the bitrate is lowered
+	bitrateLowLoss      = 0.02 // Video loss below which the bitrate is raised
+	bitrateLowerSamples = 2    // Number of consecutive high loss samples after which the bitrate is lowered
+	bitrateMax          = 5    // Mbps
+	bitrateMin          = 1    // Mbps
+	bitrateRaiseSamples = 5    // Number of consecutive low loss samples after which the bitrate is raised
+)
+
+// bitrateController picks the video bitrate based on the video loss. To prevent it from oscillating, loss has
+// to stay high or low for several consecutive samples before the bitrate changes, it takes longer to raise the
+// bitrate than to lower it, and losses in between the two thresholds reset the count.
+type bitrateController struct {
+	bitrate int // Mbps
+	high    int // Number of consecutive high loss samples
+	low     int // Number of consecutive low loss samples
+}
+
+func newBitrateController() *bitrateController {
+	// The drone streams at its highest bitrate by default
+	return &bitrateController{bitrate: bitrateMax}
+}
+
+// update returns the bitrate that should be used after the provided video loss sample, and whether it has
+// changed. The bitrate is only updated once the change has been applied with set.
+func (c *bitrateController) update(loss float64) (bitrate int, changed bool) {
+	// Update counts
+	switch {
+	case loss > bitrateHighLoss:
+		c.high++
+		c.low = 0
+	case loss < bitrateLowLoss:
+		c.high = 0
+		c.low++
+	default:
+		c.high = 0
+		c.low = 0
+	}
+
+	// Get bitrate
+	bitrate = c.bitrate
+	if c.high >= bitrateLowerSamples && bitrate > bitrateMin {
+		bitrate--
+	} else if c.low >= bitrateRaiseSamples && bitrate < bitrateMax {
+		bitrate++
+	}
+	changed = bitrate != c.bitrate
+	return
+}
+
+func (c *bitrateController) set(bitrate int) {
+	c.bitrate = bitrate
+	c.high = 0
+	c.low = 0
+}
+
+// adaptBitrate lowers the video bitrate when video loss rises and raises it back when the link recovers. It
+// only applies while video is streaming.
+func (d *Drone) adaptBitrate(q LinkQuality) {
+	// Video is not streaming
+	d.mvs.Lock()
+	on := d.videoOn
+	d.mvs.Unlock()
+	if !on {
+		return
+	}
+
+	// Update controller
+	bitrate, changed := d.abr.update(q.VideoLoss)
+	if !changed {
+		return
+	}
+
+	// Set bitrate
+	if err := d.SetVideoBitrate(bitrate); err != nil {
+		d.l.Error(fmt.Errorf("astitello: setting adaptive bitrate to %d failed: %w", bitrate, err))
+		return
+	}
+	d.abr.set(bitrate)
+	d.l.Infof("astitello: video loss is %.2f, bitrate set to %dMbps", q.VideoLoss, bitrate)
+}
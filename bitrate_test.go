@@ -0,0 +1,87 @@
+package astitello
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestBitrateController(t *testing.T) {
+	// Loop through loss samples
+	c := newBitrateController()
+	var bs []int
+	for _, loss := range []float64{
+		// A single spike doesn't change anything
+		0.5, 0,
+		// Sustained loss lowers the bitrate step by step
+		0.5, 0.5, 0.5, 0.5,
+		// Loss in between thresholds doesn't change anything
+		0.05, 0.05, 0.05, 0.05, 0.05, 0.05,
+		// Recovery needs more samples
+		0.01, 0.01, 0.01, 0.01, 0.05, 0.01, 0.01, 0.01, 0.01, 0.01,
+	} {
+		if b, changed := c.update(loss); changed {
+			c.set(b)
+			bs = append(bs, b)
+		}
+	}
+	if e := []int{4, 3, 4}; !reflect.DeepEqual(e, bs) {
+		t.Errorf("expected %+v, got %+v", e, bs)
+	}
+
+	// Bitrate is bounded
+	c = newBitrateController()
+	for i := 0; i < 100; i++ {
+		if b, changed := c.update(0); changed {
+			t.Fatalf("expected no change, got %d", b)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		if b, changed := c.update(1); changed {
+			c.set(b)
+		}
+	}
+	if e, g := bitrateMin, c.bitrate; e != g {
+		t.Errorf("expected %d, got %d", e, g)
+	}
+}
+
+func TestAdaptiveBitrate(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t, WithAdaptiveBitrate(true))
+	defer teardown()
+
+	// Capabilities are unknown, therefore bitrate cmds are attempted
+	d.resetCapabilities()
+
+	// Acknowledge bitrate cmds
+	c.mt.Lock()
+	h := c.h
+	c.h = func(cmd []byte) []byte {
+		if string(cmd) == "setbitrate 4" {
+			return []byte("ok")
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+
+	// Video is not streaming
+	for i := 0; i < 3; i++ {
+		d.adaptBitrate(LinkQuality{VideoLoss: 0.5})
+	}
+	if e, g := []string{"command", "sdk?", "sn?"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+
+	// Loss spikes while video is streaming
+	d.videoReceived()
+	for _, loss := range []float64{0.5, 0, 0.5, 0.5} {
+		d.adaptBitrate(LinkQuality{VideoLoss: loss})
+	}
+	if e, g := []string{"command", "sdk?", "sn?", "setbitrate 4"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+	if e, g := 4, d.abr.bitrate; e != g {
+		t.Error(fmt.Errorf("expected %d, got %d", e, g))
+	}
+}
@@ -0,0 +1,110 @@
+package astitello
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Capabilities represents the features supported by the connected drone, which depend on its model: the
+// basic Tello runs SDK 1.3, the Tello EDU runs SDK 2.0 and the RoboMaster Tello Talent runs SDK 3.0 and has
+// an open-source controller driving its LED, matrix display and TOF sensor
+type Capabilities struct {
+	AP           bool   `json:"ap"`            // Whether the drone can join an access point, SDK 2.0+
+	Ext          bool   `json:"ext"`           // Whether EXT cmds are supported, Tello Talent only
+	MissionPads  bool   `json:"mission_pads"`  // Whether mission pads are supported, SDK 2.0+
+	Probed       bool   `json:"probed"`        // Whether capabilities have been probed, other fields are meaningless otherwise
+	SDKVersion   string `json:"sdk_version"`   // The SDK version, empty for SDK 1.3 which doesn't support the sdk? cmd
+	SerialNumber string `json:"serial_number"` // The serial number
+	VideoConfig  bool   `json:"video_config"`  // Whether the video bitrate, resolution and fps can be set, SDK 3.0+
+}
+
+// Capabilities returns the features supported by the connected drone
+// Capabilities are probed once connected, right after the "command" handshake, by sending the sdk?, sn? and
+// "EXT tof?" cmds, and cached until the drone is closed. When WithManualHandshake is used, they're probed by
+// EnterSDKMode() instead. If the drone is not connected or probing has failed, the returned capabilities are
+// not probed.
+// Once probed, feature-specific methods return an UnsupportedError instead of sending cmds the drone doesn't
+// support. Until then, they send them anyway.
+func (d *Drone) Capabilities() Capabilities {
+	d.mcp.Lock()
+	defer d.mcp.Unlock()
+	return d.caps
+}
+
+// checkCapability returns an UnsupportedError if capabilities have been probed and the connected drone doesn't
+// support the cmd. When capabilities are unknown, the cmd is attempted.
+func (d *Drone) checkCapability(command string, supported func(c Capabilities) bool, reason string) (err error) {
+	if c := d.Capabilities(); c.Probed && !supported(c) {
+		err = &UnsupportedError{Command: command, Reason: reason}
+	}
+	return
+}
+
+// updateCapabilities probes capabilities unless they've already been probed. No lock is held while probing so
+// that cmds are not blocked.
+func (d *Drone) updateCapabilities() {
+	// Capabilities have already been probed
+	if d.Capabilities().Probed {
+		return
+	}
+
+	// Probe
+	c, err := d.probeCapabilities()
+	if err != nil {
+		d.l.Error(fmt.Errorf("astitello: probing capabilities failed: %w", err))
+		return
+	}
+
+	// Cache
+	d.mcp.Lock()
+	d.caps = c
+	d.mcp.Unlock()
+}
+
+func (d *Drone) resetCapabilities() {
+	d.mcp.Lock()
+	defer d.mcp.Unlock()
+	d.caps = Capabilities{}
+}
+
+func (d *Drone) probeCapabilities() (c Capabilities, err error) {
+	// Not connected
+	if !d.Connected() {
+		err = ErrNotConnected
+		return
+	}
+
+	// Get SDK version
+	var de *DroneError
+	if c.SDKVersion, err = d.SDKVersion(); err != nil && !errors.As(err, &de) {
+		err = fmt.Errorf("astitello: getting sdk version failed: %w", err)
+		return
+	}
+
+	// Get serial number
+	if c.SerialNumber, err = d.SerialNumber(); err != nil && !errors.As(err, &de) {
+		err = fmt.Errorf("astitello: getting serial number failed: %w", err)
+		return
+	}
+
+	// Get features provided by the SDK
+	v, _ := strconv.Atoi(c.SDKVersion)
+	c.AP = v >= 20
+	c.MissionPads = v >= 20
+	c.VideoConfig = v >= 30
+
+	// EXT cmds are only supported by the Tello Talent
+	if c.VideoConfig {
+		if _, err = d.extTOF(); err != nil && !errors.As(err, &de) {
+			err = fmt.Errorf("astitello: getting EXT tof failed: %w", err)
+			return
+		}
+		c.Ext = err == nil
+	}
+
+	// Update
+	c.Probed = true
+	err = nil
+	return
+}
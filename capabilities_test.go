@@ -0,0 +1,165 @@
+package astitello
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCapabilities(t *testing.T) {
+	for _, v := range []struct {
+		cmds  []string
+		e     Capabilities
+		name  string
+		resps map[string]string
+	}{
+		{
+			cmds: []string{"command", "sdk?", "sn?"},
+			e:    Capabilities{Probed: true, SerialNumber: "0TQDG1"},
+			name: "tello",
+			resps: map[string]string{
+				"sdk?": "unknown command: sdk?",
+				"sn?":  "0TQDG1",
+			},
+		},
+		{
+			cmds: []string{"command", "sdk?", "sn?"},
+			e:    Capabilities{AP: true, MissionPads: true, Probed: true, SDKVersion: "20", SerialNumber: "0TQZH2"},
+			name: "tello edu",
+			resps: map[string]string{
+				"sdk?": "20",
+				"sn?":  "0TQZH2",
+			},
+		},
+		{
+			cmds: []string{"command", "sdk?", "sn?", "EXT tof?"},
+			e: Capabilities{
+				AP:           true,
+				Ext:          true,
+				MissionPads:  true,
+				Probed:       true,
+				SDKVersion:   "30",
+				SerialNumber: "0TQZK3",
+				VideoConfig:  true,
+			},
+			name: "tello talent",
+			resps: map[string]string{
+				"EXT tof?": "tof 100",
+				"sdk?":     "30",
+				"sn?":      "0TQZK3",
+			},
+		},
+	} {
+		t.Run(v.name, func(t *testing.T) {
+			// Set up
+			d, c, s, vd, err := setup(t)
+			if err != nil {
+				t.Fatal(fmt.Errorf("test: setting up failed: %w", err))
+			}
+			defer func() {
+				d.Close()
+				c.close()
+				s.close()
+				vd.close()
+			}()
+
+			// Not connected
+			if g := d.Capabilities(); g.Probed {
+				t.Errorf("expected probed == false, got %+v", g)
+			}
+
+			// Emulate drone
+			c.mt.Lock()
+			h := c.h
+			c.h = func(cmd []byte) []byte {
+				if r, ok := v.resps[string(cmd)]; ok {
+					return []byte(r)
+				}
+				return h(cmd)
+			}
+			c.mt.Unlock()
+
+			// Start
+			if err = d.Start(); err != nil {
+				t.Fatal(fmt.Errorf("test: starting the drone failed: %w", err))
+			}
+
+			// Capabilities are probed once
+			for i := 0; i < 2; i++ {
+				if g := d.Capabilities(); !reflect.DeepEqual(v.e, g) {
+					t.Errorf("expected %+v, got %+v", v.e, g)
+				}
+			}
+			if g := c.received(); !reflect.DeepEqual(v.cmds, g) {
+				t.Errorf("expected %+v, got %+v", v.cmds, g)
+			}
+
+			// Capabilities are reset on close
+			d.Close()
+			if g := d.Capabilities(); g.Probed {
+				t.Errorf("expected probed == false, got %+v", g)
+			}
+		})
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	// Set up and start without probing capabilities
+	d, c, _, _, teardown := setupAndStart(t, WithManualHandshake(true))
+	defer teardown()
+
+	// Capabilities are unknown, therefore cmds are attempted
+	if err := d.SetVideoBitrate(1); err != nil {
+		t.Error(fmt.Errorf("test: setting video bitrate failed: %w", err))
+	}
+
+	// Emulate a basic Tello
+	c.mt.Lock()
+	h := c.h
+	c.h = func(cmd []byte) []byte {
+		if string(cmd) == "sdk?" {
+			return []byte("unknown command: sdk?")
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+
+	// Probe capabilities
+	if err := d.EnterSDKMode(); err != nil {
+		t.Fatal(fmt.Errorf("test: entering sdk mode failed: %w", err))
+	}
+	if g := d.Capabilities(); !g.Probed {
+		t.Fatalf("expected probed == true, got %+v", g)
+	}
+
+	// Gated cmds
+	for _, v := range []struct {
+		cmd string
+		f   func() error
+	}{
+		{cmd: "ap", f: func() error { return d.ConnectToAP("ssid", "password") }},
+		{cmd: "EXT", f: func() error { return d.SendExt("led", "0", "0", "0") }},
+		{cmd: "EXT", f: func() error { return d.SetLED(0, 0, 0) }},
+		{cmd: "EXT", f: func() error { _, err := d.ExtTOF(); return err }},
+		{cmd: "setbitrate", f: func() error { return d.SetVideoBitrate(1) }},
+		{cmd: "setfps", f: func() error { return d.SetVideoFPS(FPSLow) }},
+		{cmd: "setresolution", f: func() error { return d.SetVideoResolution(ResolutionLow) }},
+	} {
+		err := v.f()
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("%s: expected %s, got %v", v.cmd, ErrUnsupported, err)
+		}
+		var ue *UnsupportedError
+		if !errors.As(err, &ue) {
+			t.Errorf("%s: expected UnsupportedError, got %v", v.cmd, err)
+		} else if ue.Command != v.cmd {
+			t.Errorf("expected %s, got %s", v.cmd, ue.Command)
+		}
+	}
+
+	// No gated cmd has been sent
+	if e, g := []string{"setbitrate 1", "command", "sdk?", "sn?"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+}
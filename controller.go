@@ -0,0 +1,35 @@
+package astitello
+
+import "github.com/asticode/go-astikit"
+
+// Make sure implementations don't drift from the interface
+var (
+	_ Controller = (*Drone)(nil)
+	_ Controller = (*Simulator)(nil)
+)
+
+// Controller represents the methods used to fly a drone
+// Both *Drone and *Simulator implement it, which makes it possible to write control code that runs without
+// hardware and to inject mocks in tests. Lifecycle methods such as Start() and Close() are left out on
+// purpose: they belong to whoever creates the implementation.
+type Controller interface {
+	Back(x int) error
+	Curve(x1, y1, z1, x2, y2, z2, speed int) error
+	Down(x int) error
+	Emergency() error
+	Flip(x FlipDirection) error
+	Forward(x int) error
+	Go(x, y, z, speed int) error
+	Hover() error
+	Land() error
+	Left(x int) error
+	On(name string, h astikit.EventerHandler)
+	Right(x int) error
+	RotateClockwise(x int) error
+	RotateCounterClockwise(x int) error
+	SetSpeed(x int) error
+	SetSticks(lr, fb, ud, y int) error
+	State() State
+	TakeOff() error
+	Up(x int) error
+}
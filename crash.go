@@ -0,0 +1,80 @@
+package astitello
+
+import (
+	"math"
+	"time"
+
+	"github.com/asticode/go-astikit"
+)
+
+// CrashEventHandler returns the proper EventHandler for the Crash event
+// The state is the one that confirmed the crash.
+func CrashEventHandler(f func(s State)) astikit.EventerHandler {
+	return func(payload interface{}) {
+		f(payload.(State))
+	}
+}
+
+// crashDetector detects crashes from the state: while airborne, an acceleration spike followed by a height
+// dropping to zero most likely means the drone has crashed or has been caught
+type crashDetector struct {
+	acceleration float64
+	count        int
+	debounce     int
+	spikeAt      time.Time
+	window       time.Duration
+}
+
+func newCrashDetector(o options) *crashDetector {
+	return &crashDetector{
+		acceleration: o.crashAcceleration,
+		debounce:     o.crashDebounce,
+		window:       o.crashWindow,
+	}
+}
+
+// update returns whether a crash has been detected
+func (cd *crashDetector) update(s State, airborne bool, now time.Time) (crashed bool) {
+	// Detection is disabled
+	if cd.acceleration <= 0 {
+		return
+	}
+
+	// Crashes can only happen while airborne
+	if !airborne {
+		cd.reset()
+		return
+	}
+
+	// Acceleration spike
+	if math.Sqrt(s.Acceleration.X*s.Acceleration.X+s.Acceleration.Y*s.Acceleration.Y+s.Acceleration.Z*s.Acceleration.Z) >= cd.acceleration {
+		cd.spikeAt = now
+	}
+
+	// No recent spike
+	if cd.spikeAt.IsZero() || now.Sub(cd.spikeAt) > cd.window {
+		cd.reset()
+		return
+	}
+
+	// Height has not dropped to zero, which happens during flips
+	if s.Height > 0 {
+		cd.count = 0
+		return
+	}
+
+	// Make sure the drop is consistent
+	if cd.count++; cd.count < cd.debounce {
+		return
+	}
+
+	// Crash
+	cd.reset()
+	crashed = true
+	return
+}
+
+func (cd *crashDetector) reset() {
+	cd.count = 0
+	cd.spikeAt = time.Time{}
+}
@@ -0,0 +1,87 @@
+package astitello
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCrashDetector(t *testing.T) {
+	cd := newCrashDetector(options{crashAcceleration: 3000, crashDebounce: 2, crashWindow: time.Second})
+	n := time.Now()
+	for _, v := range []struct {
+		airborne bool
+		crashed  bool
+		d        time.Duration
+		s        State
+	}{
+		// Flip: spike but height doesn't drop
+		{airborne: true, s: State{Acceleration: Acceleration{Z: -4000}, Height: 100}},
+		{airborne: true, s: State{Height: 100}},
+		// Height drops to zero too long after the spike
+		{airborne: true, d: 2 * time.Second, s: State{Height: 0}},
+		{airborne: true, d: 2 * time.Second, s: State{Height: 0}},
+		// Not airborne
+		{s: State{Acceleration: Acceleration{X: 3000, Y: 3000}, Height: 0}},
+		{s: State{Height: 0}},
+		// Crash
+		{airborne: true, d: 3 * time.Second, s: State{Acceleration: Acceleration{X: 3000, Y: 3000}, Height: 50}},
+		{airborne: true, d: 3 * time.Second, s: State{Height: 0}},
+		{airborne: true, crashed: true, d: 3 * time.Second, s: State{Height: 0}},
+		// Detector has been reset
+		{airborne: true, d: 3 * time.Second, s: State{Height: 0}},
+	} {
+		if g := cd.update(v.s, v.airborne, n.Add(v.d)); g != v.crashed {
+			t.Errorf("%+v: expected %v, got %v", v, v.crashed, g)
+		}
+	}
+
+	// Disabled
+	cd = newCrashDetector(options{})
+	for i := 0; i < 3; i++ {
+		if cd.update(State{Acceleration: Acceleration{Z: -5000}}, true, n) {
+			t.Error("expected no crash")
+		}
+	}
+}
+
+func TestCrashDetection(t *testing.T) {
+	// Set up and start
+	d, _, s, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Handle crash
+	crashes := make(chan State, 1)
+	d.On(CrashEvent, CrashEventHandler(func(s State) { crashes <- s }))
+
+	// Take off
+	if err := d.TakeOff(); err != nil {
+		t.Fatal(fmt.Errorf("test: taking off failed: %w", err))
+	}
+
+	// Write crash-like states
+	for _, v := range []struct {
+		agz float64
+		h   int
+	}{
+		{agz: -1000, h: 50},
+		{agz: -4500, h: 40},
+		{agz: -1000, h: 0},
+		{agz: -1000, h: 0},
+	} {
+		if _, err := s.conn.Write([]byte(fmt.Sprintf("pitch:0;roll:0;yaw:0;vgx:0;vgy:0;vgz:0;templ:0;temph:0;tof:0;h:%d;bat:0;baro:0.0;time:0;agx:0.0;agy:0.0;agz:%.1f;", v.h, v.agz))); err != nil {
+			t.Fatal(fmt.Errorf("test: writing state failed: %w", err))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Check
+	select {
+	case s := <-crashes:
+		if s.Height != 0 {
+			t.Errorf("expected height 0, got %d", s.Height)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected crash")
+	}
+}
@@ -0,0 +1,126 @@
+package astitello
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"sync"
+	"sync/atomic"
+
+	"github.com/asticode/go-astikit"
+)
+
+// Decoder represents an object capable of decoding H264 access units into images
+// Decode is called sequentially, in stream order, and the first access unit is always a keyframe. Since
+// decoders may buffer access units internally, it can return no image at all.
+// No decoder is shipped with this package: wire the one of your choice (cgo/ffmpeg, pure Go, etc.)
+type Decoder interface {
+	Decode(f VideoFrame) ([]image.Image, error)
+}
+
+// VideoImageEventHandler returns the proper EventHandler for the VideoImage event
+func VideoImageEventHandler(f func(i image.Image)) astikit.EventerHandler {
+	return func(payload interface{}) {
+		f(payload.(image.Image))
+	}
+}
+
+// videoDecoder decodes access units in a dedicated goroutine so that a slow decoder doesn't block reading
+// the video stream. When its buffer is full, access units are dropped until the next keyframe since the
+// following ones can't be decoded properly anyway.
+type videoDecoder struct {
+	c            *sync.Cond
+	d            Decoder
+	dropped      *uint64 // Incremented atomically when access units are dropped, if not nil
+	fs           []VideoFrame
+	size         int
+	waitKeyframe bool
+}
+
+func newVideoDecoder(d Decoder, size int) *videoDecoder {
+	return &videoDecoder{
+		c:            sync.NewCond(&sync.Mutex{}),
+		d:            d,
+		size:         size,
+		waitKeyframe: true,
+	}
+}
+
+func (vd *videoDecoder) add(f VideoFrame) {
+	// Lock
+	vd.c.L.Lock()
+	defer vd.c.L.Unlock()
+
+	// Buffer is full
+	if len(vd.fs) >= vd.size {
+		vd.drop(len(vd.fs))
+		vd.fs = vd.fs[:0]
+		vd.waitKeyframe = true
+	}
+
+	// We need a keyframe
+	if vd.waitKeyframe {
+		if !f.Keyframe {
+			vd.drop(1)
+			return
+		}
+		vd.waitKeyframe = false
+	}
+
+	// Append
+	vd.fs = append(vd.fs, f)
+
+	// Signal
+	vd.c.Signal()
+}
+
+func (vd *videoDecoder) drop(n int) {
+	if vd.dropped != nil {
+		atomic.AddUint64(vd.dropped, uint64(n))
+	}
+}
+
+func (vd *videoDecoder) start(ctx context.Context, fn func(i image.Image), l astikit.SeverityLogger) {
+	// Handle context
+	go func() {
+		// Wait for context to be done
+		<-ctx.Done()
+
+		// Signal
+		vd.c.L.Lock()
+		vd.c.Signal()
+		vd.c.L.Unlock()
+	}()
+
+	// Loop
+	for {
+		// Wait for an access unit
+		vd.c.L.Lock()
+		for len(vd.fs) == 0 && ctx.Err() == nil {
+			vd.c.Wait()
+		}
+
+		// Check context
+		if ctx.Err() != nil {
+			vd.c.L.Unlock()
+			return
+		}
+
+		// Shift
+		f := vd.fs[0]
+		vd.fs = vd.fs[1:]
+		vd.c.L.Unlock()
+
+		// Decode
+		is, err := vd.d.Decode(f)
+		if err != nil {
+			l.Error(fmt.Errorf("astitello: decoding video frame failed: %w", err))
+			continue
+		}
+
+		// Loop through images
+		for _, i := range is {
+			fn(i)
+		}
+	}
+}
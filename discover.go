@@ -0,0 +1,149 @@
+package astitello
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"time"
+)
+
+// Discovery defaults
+var (
+	discoverInterval = 500 * time.Millisecond
+	discoverTargets  = broadcastAddrs
+	discoverTimeout  = 3 * time.Second
+)
+
+// Discover looks for drones on the LAN, which is needed once they have joined an access point through
+// ConnectToAP() since they get an address through DHCP, and returns their cmd addresses, which can be provided
+// to Reconnect() or WithCommandAddr().
+// It sends the "command" cmd to the broadcast address of every IPv4 interface, as well as to the drone's default
+// address, every 500ms until the context is done or 3s have passed if the context has no deadline. Drones that
+// respond are returned. This is best-effort: an error is only returned when discovery can't be set up.
+func Discover(ctx context.Context) (addrs []string, err error) {
+	// Make sure discovery is time-bounded
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, discoverTimeout)
+		defer cancel()
+	}
+
+	// Get targets
+	var ts []string
+	if ts, err = discoverTargets(); err != nil {
+		err = fmt.Errorf("astitello: getting discovery targets failed: %w", err)
+		return
+	}
+
+	// Resolve targets
+	var raddrs []*net.UDPAddr
+	for _, t := range ts {
+		var raddr *net.UDPAddr
+		if raddr, err = net.ResolveUDPAddr("udp4", t); err != nil {
+			err = fmt.Errorf("astitello: resolving %s failed: %w", t, err)
+			return
+		}
+		raddrs = append(raddrs, raddr)
+	}
+
+	// Listen on a random port so that it doesn't conflict with a started drone
+	var conn *net.UDPConn
+	if conn, err = net.ListenUDP("udp4", &net.UDPAddr{}); err != nil {
+		err = fmt.Errorf("astitello: listening failed: %w", err)
+		return
+	}
+	defer conn.Close()
+
+	// Loop
+	found := make(map[string]bool)
+	b := make([]byte, 2048)
+	for {
+		// Send cmd to all targets
+		for _, raddr := range raddrs {
+			// Some interfaces may not allow broadcasting, which is not a reason to stop
+			conn.WriteToUDP([]byte("command"), raddr)
+		}
+
+		// Get read deadline
+		deadline, _ := ctx.Deadline()
+		if n := time.Now().Add(discoverInterval); n.Before(deadline) {
+			deadline = n
+		}
+		conn.SetReadDeadline(deadline)
+
+		// Read responses until deadline
+		for {
+			n, addr, err := conn.ReadFromUDP(b)
+			if err != nil {
+				var nerr net.Error
+				if !errors.As(err, &nerr) || !nerr.Timeout() {
+					return nil, fmt.Errorf("astitello: reading failed: %w", err)
+				}
+				break
+			}
+
+			// Only drones respond "ok"
+			if string(b[:n]) == "ok" {
+				found[addr.String()] = true
+			}
+		}
+
+		// Context is done
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	// Sort addresses
+	for a := range found {
+		addrs = append(addrs, a)
+	}
+	sort.Strings(addrs)
+	return
+}
+
+func broadcastAddrs() (addrs []string, err error) {
+	// Get interfaces
+	var is []net.Interface
+	if is, err = net.Interfaces(); err != nil {
+		err = fmt.Errorf("astitello: getting interfaces failed: %w", err)
+		return
+	}
+
+	// The drone's default address is always probed
+	addrs = append(addrs, cmdAddr)
+
+	// Loop through interfaces
+	_, port, _ := net.SplitHostPort(cmdAddr)
+	for _, i := range is {
+		// Interface is down, or can't broadcast
+		if i.Flags&net.FlagUp == 0 || i.Flags&net.FlagBroadcast == 0 || i.Flags&net.FlagLoopback > 0 {
+			continue
+		}
+
+		// Get addresses
+		as, err := i.Addrs()
+		if err != nil {
+			continue
+		}
+
+		// Loop through addresses
+		for _, a := range as {
+			// Only IPv4 networks can broadcast
+			n, ok := a.(*net.IPNet)
+			if !ok || n.IP.To4() == nil || len(n.Mask) != net.IPv4len {
+				continue
+			}
+
+			// Get broadcast address
+			ip := make(net.IP, net.IPv4len)
+			for idx, b := range n.IP.To4() {
+				ip[idx] = b | ^n.Mask[idx]
+			}
+			addrs = append(addrs, net.JoinHostPort(ip.String(), port))
+		}
+	}
+	return
+}
@@ -0,0 +1,65 @@
+package astitello
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiscover(t *testing.T) {
+	// Create responders
+	var ts []string
+	for _, resp := range []string{"ok", ""} {
+		conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		if err != nil {
+			t.Fatal(fmt.Errorf("test: listening failed: %w", err))
+		}
+		defer conn.Close()
+		ts = append(ts, conn.LocalAddr().String())
+		go func(resp string) {
+			b := make([]byte, 2048)
+			for {
+				n, addr, err := conn.ReadFromUDP(b)
+				if err != nil {
+					return
+				}
+				if string(b[:n]) == "command" && resp != "" {
+					conn.WriteToUDP([]byte(resp), addr)
+				}
+			}
+		}(resp)
+	}
+
+	// Update defaults
+	dt, di := discoverTargets, discoverInterval
+	discoverTargets = func() ([]string, error) { return ts, nil }
+	discoverInterval = 10 * time.Millisecond
+	defer func() { discoverTargets, discoverInterval = dt, di }()
+
+	// Discover
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	n := time.Now()
+	addrs, err := Discover(ctx)
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: discovering failed: %w", err))
+	}
+	if e := ts[:1]; !reflect.DeepEqual(e, addrs) {
+		t.Errorf("expected %+v, got %+v", e, addrs)
+	}
+	if g := time.Since(n); g > time.Second {
+		t.Errorf("expected discovery to stop with the context, took %s", g)
+	}
+
+	// Broadcast addresses
+	bs, err := broadcastAddrs()
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: getting broadcast addresses failed: %w", err))
+	}
+	if len(bs) == 0 || bs[0] != cmdAddr {
+		t.Errorf("expected %s to be probed, got %+v", cmdAddr, bs)
+	}
+}
@@ -0,0 +1,79 @@
+package astitello
+
+import (
+	"fmt"
+	"math"
+)
+
+// displacement represents the position relative to the take off position, estimated from the cmds that
+// succeeded. Coordinates are in cm and expressed in the take off frame: x forward, y left, z up. The heading
+// is in degree, clockwise.
+type displacement struct {
+	heading float64
+	x, y, z float64
+}
+
+// move adds a move expressed in the drone's frame
+func (p *displacement) move(dx, dy, dz int) {
+	s, c := math.Sincos(p.heading * math.Pi / 180)
+	p.x += float64(dx)*c + float64(dy)*s
+	p.y += -float64(dx)*s + float64(dy)*c
+	p.z += float64(dz)
+}
+
+// rotate adds a clockwise rotation
+func (p *displacement) rotate(deg int) {
+	p.heading = math.Mod(p.heading+float64(deg), 360)
+}
+
+// toStart returns the move, expressed in the drone's frame, leading back to the take off position
+func (p displacement) toStart() (dx, dy, dz int) {
+	s, c := math.Sincos(p.heading * math.Pi / 180)
+	dx = int(math.Round(-p.x*c + p.y*s))
+	dy = int(math.Round(-p.x*s - p.y*c))
+	dz = int(math.Round(-p.z))
+	return
+}
+
+func (d *Drone) resetDisplacement() {
+	d.mdp.Lock()
+	defer d.mdp.Unlock()
+	d.dp = displacement{}
+}
+
+func (d *Drone) addMove(dx, dy, dz int) {
+	d.mdp.Lock()
+	defer d.mdp.Unlock()
+	d.dp.move(dx, dy, dz)
+}
+
+func (d *Drone) addRotation(deg int) {
+	d.mdp.Lock()
+	defer d.mdp.Unlock()
+	d.dp.rotate(deg)
+}
+
+// ReturnToStart makes Tello fly back to its take off position, at take off height, with a single go cmd at
+// speed (cm/s). Nothing is sent if the drone is less than 20cm away on every axis.
+// The position is estimated from the up, down, left, right, forward, back, go, curve, cw and ccw cmds that
+// succeeded since the last take off: this is dead-reckoning, therefore drift, wind, flips, rc cmds and cmds
+// interrupted by an emergency are not taken into account and errors add up over time. The go cmd fails if
+// the drone is more than 500cm away on any axis.
+func (d *Drone) ReturnToStart(speed int) (err error) {
+	// Get move
+	d.mdp.Lock()
+	dx, dy, dz := d.dp.toStart()
+	d.mdp.Unlock()
+
+	// Already at start
+	if abs(dx) <= 20 && abs(dy) <= 20 && abs(dz) <= 20 {
+		return
+	}
+
+	// Go
+	if err = d.Go(dx, dy, dz, speed); err != nil {
+		err = fmt.Errorf("astitello: returning to start failed: %w", err)
+		return
+	}
+	return
+}
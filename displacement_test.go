@@ -0,0 +1,78 @@
+package astitello
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestDisplacement(t *testing.T) {
+	var p displacement
+	toStart := func() []int {
+		dx, dy, dz := p.toStart()
+		return []int{dx, dy, dz}
+	}
+
+	// Move
+	p.move(100, 0, 50)
+	p.rotate(90)
+	p.move(100, 0, 0)
+	if e, g := []int{-100, -100, -50}, toStart(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+	p.rotate(-45)
+	p.rotate(-45)
+	p.move(0, 30, 0)
+	if e, g := []int{-100, 70, -50}, toStart(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+
+	// Moving back should lead to the start
+	v := toStart()
+	p.move(v[0], v[1], v[2])
+	if e, g := []int{0, 0, 0}, toStart(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+}
+
+func TestReturnToStart(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Respond to all cmds
+	c.mt.Lock()
+	c.h = func([]byte) []byte { return []byte("ok") }
+	c.mt.Unlock()
+
+	// Fly
+	for _, f := range []func() error{
+		d.TakeOff,
+		func() error { return d.Forward(100) },
+		func() error { return d.RotateClockwise(90) },
+		func() error { return d.Forward(100) },
+		func() error { return d.ReturnToStart(50) },
+		func() error { return d.ReturnToStart(50) },
+	} {
+		if err := f(); err != nil {
+			t.Error(fmt.Errorf("test: cmd failed: %w", err))
+		}
+	}
+
+	// Second return should be a no-op
+	if e, g := []string{"command", "sdk?", "sn?", "takeoff", "forward 100", "cw 90", "forward 100", "go -100 -100 0 50"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+
+	// Take off should reset displacement
+	d.addMove(100, 0, 0)
+	if err := d.TakeOff(); err != nil {
+		t.Error(fmt.Errorf("test: taking off failed: %w", err))
+	}
+	if err := d.ReturnToStart(50); err != nil {
+		t.Error(fmt.Errorf("test: returning to start failed: %w", err))
+	}
+	if cmds := c.received(); cmds[len(cmds)-1] != "takeoff" {
+		t.Errorf("expected takeoff, got %s", cmds[len(cmds)-1])
+	}
+}
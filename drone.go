@@ -5,9 +5,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"image"
+	"math"
 	"net"
-	"strconv"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/asticode/go-astikit"
@@ -15,65 +19,346 @@ import (
 
 // Defaults
 var (
-	defaultTimeout = 5 * time.Second
-	cmdAddr        = "192.168.10.1:8889"
-	respAddr       = ":8889"
-	stateAddr      = ":8890"
-	videoAddr      = ":11111"
+	defaultTimeout     = 5 * time.Second
+	flipTimeout        = 20 * time.Second
+	landTimeout        = 20 * time.Second
+	moveTimeout        = time.Minute
+	takeOffTimeout     = 20 * time.Second
+	landOnCloseTimeout = time.Second
+	closeWaitTimeout   = time.Second
+	readErrorBackoff   = 10 * time.Millisecond
+	readErrorMaxSleep  = time.Second
+	stateErrorInterval = 10 * time.Second
+	cmdAddr            = "192.168.10.1:8889"
+	respAddr           = ":8889"
+	stateAddr          = ":8890"
+	videoAddr          = ":11111"
+	videoChanSize      = 30
+	videoDecoderSize   = 30
+	videoFeedInterval  = time.Second / 30
+	videoFlushTimeout  = 50 * time.Millisecond
+)
+
+// Video
+var (
+	videoStartCode = []byte{0x0, 0x0, 0x0, 0x1}
 )
 
 // Events
 const (
-	LandEvent        = "land"
-	StateEvent       = "state"
-	TakeOffEvent     = "take.off"
-	VideoPacketEvent = "video.packet"
+	AirborneEvent      = "airborne"
+	CommandEvent       = "command"
+	ConnectEvent       = "connect"
+	CrashEvent         = "crash"
+	DisconnectEvent    = "disconnect"
+	ErrorEvent         = "error"
+	GroundedEvent      = "grounded"
+	LandEvent          = "land"
+	LinkQualityEvent   = "link.quality"
+	ReconnectedEvent   = "reconnected"
+	ReconnectingEvent  = "reconnecting"
+	ResponseEvent      = "response"
+	StateEvent         = "state"
+	TakeOffEvent       = "take.off"
+	TelemetryLostEvent = "telemetry.lost"
+	VideoFrameEvent    = "video.frame"
+	VideoImageEvent    = "video.image"
+	VideoPacketEvent   = "video.packet"
+	VideoStartedEvent  = "video.started"
+	VideoStatsEvent    = "video.stats"
+	VideoStoppedEvent  = "video.stopped"
+	WifiChangedEvent   = "wifi.changed"
 )
 
 // Flip directions
 const (
-	FlipBack    = "b"
-	FlipForward = "f"
-	FlipLeft    = "l"
-	FlipRight   = "r"
+	FlipBack    FlipDirection = "b"
+	FlipForward FlipDirection = "f"
+	FlipLeft    FlipDirection = "l"
+	FlipRight   FlipDirection = "r"
+)
+
+// FlipDirection represents a flip direction
+type FlipDirection string
+
+// ParseFlipDirection parses a flip direction such as "l" or "left"
+func ParseFlipDirection(s string) (x FlipDirection, err error) {
+	switch strings.ToLower(s) {
+	case "b", "back":
+		x = FlipBack
+	case "f", "forward":
+		x = FlipForward
+	case "l", "left":
+		x = FlipLeft
+	case "r", "right":
+		x = FlipRight
+	default:
+		err = fmt.Errorf("astitello: unknown flip direction %q: %w", s, ErrInvalidArgument)
+	}
+	return
+}
+
+func validateFlipDirection(x FlipDirection) (err error) {
+	switch x {
+	case FlipBack, FlipForward, FlipLeft, FlipRight:
+	default:
+		err = fmt.Errorf("astitello: unknown flip direction %q: %w", x, ErrInvalidArgument)
+	}
+	return
+}
+
+// Video FPS
+const (
+	FPSHigh   FPS = "high"
+	FPSLow    FPS = "low"
+	FPSMiddle FPS = "middle"
+)
+
+// FPS represents a video frame rate
+type FPS string
+
+// Video resolutions
+const (
+	ResolutionHigh Resolution = "high"
+	ResolutionLow  Resolution = "low"
+)
+
+// Resolution represents a video resolution
+type Resolution string
+
+// Errors
+var (
+	// ErrBatteryTooLow is the error thrown when taking off with a battery below the level provided to
+	// WithMinTakeoffBattery
+	ErrBatteryTooLow = errors.New("astitello: battery too low")
+	// ErrInvalidArgument is the error thrown when a cmd argument is out of the range accepted by the SDK
+	ErrInvalidArgument = errors.New("astitello: invalid argument")
+	// ErrNotAirborne is the error thrown when sending a movement cmd while the drone is on the ground or landing
+	ErrNotAirborne = errors.New("astitello: not airborne")
+	// ErrNotConnected is the error thrown when trying to send a cmd while not connected to the drone
+	ErrNotConnected = errors.New("astitello: not connected")
+	// ErrShuttingDown is the error thrown when trying to send a cmd while the drone is shutting down
+	ErrShuttingDown = errors.New("astitello: shutting down")
+	// ErrStillClosing is the error thrown when starting the drone while goroutines of the previous session, which
+	// may still use its sockets, are not done yet
+	ErrStillClosing = errors.New("astitello: still closing")
+	// ErrTimeout is the error thrown when no response has been received before the cmd's timeout
+	ErrTimeout = errors.New("astitello: timeout")
+	// ErrUnsupported is the error thrown when the connected drone doesn't support a cmd. Check out
+	// UnsupportedError for details.
+	ErrUnsupported = errors.New("astitello: unsupported")
+	// ErrVideoUnavailable is the error thrown when starting video while the video connection couldn't be created
+	// when connecting, which only happens when WithVideoOptional is used
+	ErrVideoUnavailable = errors.New("astitello: video unavailable")
 )
 
-// ErrNotConnected is the error thrown when trying to send a cmd while not connected to the drone
-var ErrNotConnected = errors.New("astitello: not connected")
+// CommandResult represents the outcome of a cmd
+type CommandResult struct {
+	Command string        `json:"command"`         // The cmd with its arguments, e.g. "up 20"
+	Elapsed time.Duration `json:"elapsed"`         // Includes the time spent waiting for previous cmds to be done and retries
+	Err     error         `json:"-"`               // The error, nil on success
+	Error   string        `json:"error,omitempty"` // The error's message, which is what is serialized
+}
+
+// CommandError represents a cmd that failed, whatever the cause, which is useful to know which cmd of a
+// sequence failed. errors.Is and errors.As match its cause.
+type CommandError struct {
+	Command string // The cmd with its arguments, e.g. "up 20"
+	Err     error
+}
+
+// Error implements the error interface
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("astitello: cmd %q failed: %s", e.Command, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// DroneError represents an error response sent by the drone
+type DroneError struct {
+	Raw string // The response, e.g. "error Motor stop"
+}
+
+// Error implements the error interface
+func (e *DroneError) Error() string {
+	return fmt.Sprintf("astitello: drone responded with %q", e.Raw)
+}
+
+// SpeedMismatchError represents a speed the drone didn't apply as requested, which happens when the firmware
+// silently clamps it
+type SpeedMismatchError struct {
+	Applied   int // The speed reported by the drone after the speed cmd (cm/s)
+	Requested int // The speed provided to the speed cmd (cm/s)
+}
+
+// Error implements the error interface
+func (e *SpeedMismatchError) Error() string {
+	return fmt.Sprintf("astitello: speed %d was requested but %d was applied", e.Requested, e.Applied)
+}
+
+// TransportError represents an error that occurred while exchanging with the drone over the network
+type TransportError struct {
+	Err error
+}
+
+// Error implements the error interface
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("astitello: transport failed: %s", e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// UnsupportedError represents a cmd the connected drone doesn't support, according to its capabilities
+// It matches ErrUnsupported when using errors.Is.
+type UnsupportedError struct {
+	Command string // The cmd, e.g. "setbitrate"
+	Reason  string // Why the cmd is not supported, e.g. "requires SDK 3.0"
+}
+
+// Error implements the error interface
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("astitello: %s is not supported: %s", e.Command, e.Reason)
+}
+
+// Is implements the errors.Is interface
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrUnsupported
+}
+
+// Wi-Fi parts
+const (
+	WifiPartPassword = "password"
+	WifiPartSSID     = "ssid"
+)
+
+// WifiError represents a Wi-Fi change the drone has rejected, which may have been partly applied
+// errors.As matches the DroneError it wraps.
+type WifiError struct {
+	Err  error
+	Part string // The part that failed, either WifiPartPassword or WifiPartSSID, empty if it couldn't be verified
+}
+
+// Error implements the error interface
+func (e *WifiError) Error() string {
+	p := e.Part
+	if p == "" {
+		p = "unknown part"
+	}
+	return fmt.Sprintf("astitello: setting wifi failed (%s): %s", p, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *WifiError) Unwrap() error {
+	return e.Err
+}
 
 // Drone represents an object capable of interacting with the SDK
+// Its lifecycle is the following: create it with New(), connect to the drone with Start(), send cmds, disconnect
+// with Close(). Once closed, it can be started again to reconnect.
+// Connect() and Disconnect() are aliases of Start() and Close().
 type Drone struct {
-	cancel    context.CancelFunc
-	cmdConn   *net.UDPConn
-	cmds      map[*cmd]bool
-	ctx       context.Context
-	e         *astikit.Eventer
-	l         astikit.SeverityLogger
-	lr        string
-	mc        *sync.Mutex // Locks cmds
-	ms        *sync.Mutex // Locks s
-	msc       *sync.Mutex // Locks sendCmd
-	ol        *sync.Once  // Limits Close()
-	oo        *sync.Once  // Limits Connect()
-	rc        *sync.Cond
-	s         *State
-	stateConn *net.UDPConn
-	videoConn *net.UDPConn
+	abr          *bitrateController
+	activity     Activity
+	cancel       context.CancelFunc
+	caps         Capabilities
+	cmdConn      *net.UDPConn
+	cmds         map[*cmd]bool
+	connected    bool
+	ctx          context.Context
+	dp           displacement
+	drained      chan struct{} // Closed once there are no more cmds while shutting down
+	e            *eventer
+	fs           FlightState
+	l            astikit.SeverityLogger
+	lq           linkQuality
+	mc           *sync.Mutex // Locks cmds, drained and shuttingDown
+	mcn          *sync.Mutex // Locks cancel, connected, ctx and wgDone
+	mco          *sync.Mutex // Locks cmdConn, raddr, stateConn and videoConn
+	mcp          *sync.Mutex // Locks caps
+	mdp          *sync.Mutex // Locks dp
+	mlq          *sync.Mutex // Locks lq
+	mrc          *sync.Mutex // Locks rcSending and rcSticks
+	ms           *sync.Mutex // Locks activity, fs, rawState, s, stateAt, stateErrAt and stateErrs
+	msc          *sync.Mutex // Locks sendCmd
+	mvs          *sync.Mutex // Locks videoAt, videoIdle, videoStarted and videoOn
+	mw           *sync.Mutex // Locks waiting
+	o            options
+	ol           *sync.Once // Limits Close()
+	oo           *sync.Once // Limits Connect()
+	raddr        string     // Cmd address set by Reconnect()
+	rawState     string
+	rcSending    bool
+	rcSticks     *[4]int // Sticks positions waiting to be sent when WithRCMaxRate is used
+	s            *State
+	shuttingDown bool
+	stateAt      time.Time
+	stateErrAt   time.Time // Time an invalid state was last logged
+	stateErrs    int       // Number of invalid states not logged since stateErrAt
+	stateConn    *net.UDPConn
+	vb           *videoBuffer
+	vd           *videoDecoder
+	vfp          *videoFrameParser
+	videoAt      time.Time // Time the last video datagram was received
+	videoConn    *net.UDPConn
+	videoIdle    *time.Timer   // Fires when no video datagram has been received for a while
+	videoReset   int32         // Set to 1 when the pending video packet must be discarded
+	videoStarted chan struct{} // Closed once a datagram has been received after StartVideo
+	videoOn      bool
+	videoOrphan  bool            // Set from a datagram whose packet's first datagram was lost until the next start code
+	vs           *VideoStats     // Updated atomically
+	waiting      []*cmd          // Cmds waiting for a response, in the order they've been sent
+	wg           *sync.WaitGroup // Waits for read goroutines
+	wgDone       chan struct{}   // Closed once wg is done after closing
 }
 
 // New creates a new Drone
-func New(l astikit.StdLogger) *Drone {
-	return &Drone{
+func New(l astikit.StdLogger, opts ...Option) (d *Drone) {
+	d = &Drone{
 		cmds: make(map[*cmd]bool),
-		e:    astikit.NewEventer(astikit.EventerOptions{}),
+		e:    newEventer(),
+		fs:   FlightStateUnknown,
 		l:    astikit.AdaptStdLogger(l),
 		mc:   &sync.Mutex{},
+		mcn:  &sync.Mutex{},
+		mco:  &sync.Mutex{},
+		mcp:  &sync.Mutex{},
+		mdp:  &sync.Mutex{},
+		mlq:  &sync.Mutex{},
+		mrc:  &sync.Mutex{},
 		msc:  &sync.Mutex{},
+		mvs:  &sync.Mutex{},
+		mw:   &sync.Mutex{},
 		ms:   &sync.Mutex{},
+		o:    newOptions(opts),
 		ol:   &sync.Once{},
 		oo:   &sync.Once{},
-		rc:   sync.NewCond(&sync.Mutex{}),
 		s:    &State{},
+		vs:   &VideoStats{},
+		wg:   &sync.WaitGroup{},
+	}
+	d.activity = ActivityUnknown
+	d.e.onPanic = d.handlerPanicked
+	if d.o.eventWorkers > 0 {
+		d.e.workers = make(chan struct{}, d.o.eventWorkers)
+	}
+	return
+}
+
+// handlerPanicked logs the panic of an event handler and dispatches it through the Error event
+func (d *Drone) handlerPanicked(name string, v interface{}) {
+	// Log
+	err := fmt.Errorf("astitello: %s event handler panicked: %v", name, v)
+	d.l.Error(err)
+
+	// Dispatch, unless an Error event handler panicked which would loop forever
+	if name != ErrorEvent {
+		d.e.Dispatch(ErrorEvent, err)
 	}
 }
 
@@ -84,19 +369,289 @@ func (d *Drone) State() State {
 	return *d.s
 }
 
+// StateAge returns the time elapsed since the last valid state was received
+// If no state has been received yet, it returns the maximum duration.
+func (d *Drone) StateAge() time.Duration {
+	d.ms.Lock()
+	defer d.ms.Unlock()
+	if d.stateAt.IsZero() {
+		return math.MaxInt64
+	}
+	return time.Since(d.stateAt)
+}
+
+// StateFresh returns whether a valid state has been received less than max ago. It is convenient to wait for
+// real data right after connecting since State() returns a zero-valued struct until then.
+func (d *Drone) StateFresh(max time.Duration) bool {
+	return d.StateAge() <= max
+}
+
+// RawState returns the last state datagram received from the drone, even if it couldn't be parsed, which
+// is convenient to debug firmware quirks
+func (d *Drone) RawState() string {
+	d.ms.Lock()
+	defer d.ms.Unlock()
+	return d.rawState
+}
+
+// PendingCommands returns the cmds that are either waiting to be sent or waiting for a response, sorted
+// alphabetically. It helps understanding why a cmd is blocked behind another one.
+func (d *Drone) PendingCommands() (cmds []string) {
+	// Lock
+	d.mc.Lock()
+	defer d.mc.Unlock()
+
+	// Loop through cmds
+	for c := range d.cmds {
+		cmds = append(cmds, c.cmd)
+	}
+
+	// Sort
+	sort.Strings(cmds)
+	return
+}
+
 // On adds an event handler
+// Handlers of the same event run sequentially in registration order, after handlers added with a higher
+// priority through OnPriority(). Handlers are removed when the drone is closed.
 func (d *Drone) On(name string, h astikit.EventerHandler) {
 	d.e.On(name, h)
 }
 
+// OnPriority adds an event handler that runs before handlers of the same event with a lower priority, which is
+// useful to make sure a safety handler, such as one landing the drone when its battery is low, runs before UI
+// handlers. On() uses a priority of 0.
+func (d *Drone) OnPriority(name string, priority int, h astikit.EventerHandler) {
+	d.e.OnPriority(name, priority, h)
+}
+
+// onUntil adds an event handler that is removed once the context is done
+func (d *Drone) onUntil(ctx context.Context, name string, h astikit.EventerHandler) {
+	d.onUntilTimed(ctx, name, func(_ time.Time, payload interface{}) { h(payload) })
+}
+
+// onUntilTimed is like onUntil but the handler also receives the time the event has been dispatched at
+func (d *Drone) onUntilTimed(ctx context.Context, name string, h eventerTimedHandler) {
+	// Add handler
+	off := d.e.onTimed(name, func(at time.Time, payload interface{}) {
+		// Events may have been dispatched before the handler was removed
+		if ctx.Err() != nil {
+			return
+		}
+		h(at, payload)
+	})
+
+	// Remove handler once the context is done
+	go func() {
+		<-ctx.Done()
+		off()
+	}()
+}
+
+// sendUntil returns a func running send unless the context is done, and runs closeFn once the context is done.
+// Both are run while locked, which makes sure nothing is sent on a channel closed by closeFn.
+func sendUntil(ctx context.Context, closeFn func()) func(send func()) {
+	// Close once the context is done
+	closed := false
+	m := &sync.Mutex{} // Locks closed
+	go func() {
+		// Wait for context to be done
+		<-ctx.Done()
+
+		// Close
+		m.Lock()
+		defer m.Unlock()
+		closed = true
+		closeFn()
+	}()
+	return func(send func()) {
+		// Lock
+		m.Lock()
+		defer m.Unlock()
+
+		// Channel is closed
+		if closed {
+			return
+		}
+
+		// Send
+		send()
+	}
+}
+
+// writeUntil runs write for every event of the name until the context is done or write fails, and then runs
+// flush unless write has failed. Both are run while locked.
+func (d *Drone) writeUntil(ctx context.Context, name string, write func(payload interface{}) error, flush func() error) (err error) {
+	// Create context
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Handle events
+	done := false
+	m := &sync.Mutex{} // Locks done and err
+	d.onUntil(ctx, name, func(payload interface{}) {
+		// Lock
+		m.Lock()
+		defer m.Unlock()
+
+		// Writing is over
+		if done || err != nil {
+			return
+		}
+
+		// Write
+		if err = write(payload); err != nil {
+			cancel()
+			return
+		}
+	})
+
+	// Wait for context to be done
+	<-ctx.Done()
+
+	// Lock
+	m.Lock()
+	defer m.Unlock()
+
+	// Update
+	done = true
+
+	// Writing failed
+	if err != nil {
+		return
+	}
+
+	// Flush
+	err = flush()
+	return
+}
+
+// sessionContext returns the context of the current session, which is done once the drone is closed, or nil if
+// the drone has never been started. Goroutines started by Start() can use d.ctx directly.
+func (d *Drone) sessionContext() context.Context {
+	d.mcn.Lock()
+	defer d.mcn.Unlock()
+	return d.ctx
+}
+
+// Connected returns whether the drone is connected, i.e. the "command" cmd has succeeded and the drone has
+// not been closed since
+func (d *Drone) Connected() bool {
+	d.mcn.Lock()
+	defer d.mcn.Unlock()
+	return d.connected
+}
+
+// WaitForConnection blocks until the drone is connected or the context is done, which is convenient when
+// Start() is retried in another goroutine
+func (d *Drone) WaitForConnection(ctx context.Context) (err error) {
+	// Create context
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Handle connect event before checking the connection state so that it can't be missed
+	connected := make(chan struct{}, 1)
+	d.onUntil(ctx, ConnectEvent, func(interface{}) {
+		select {
+		case connected <- struct{}{}:
+		default:
+		}
+	})
+
+	// Already connected
+	if d.Connected() {
+		return
+	}
+
+	// Wait
+	select {
+	case <-connected:
+	case <-ctx.Done():
+		err = fmt.Errorf("astitello: waiting for connection failed: %w", ctx.Err())
+	}
+	return
+}
+
+// WaitForState blocks until a valid state has been received or the context is done, and returns it. Reading
+// State() right after connecting returns a zero-valued struct, this makes sure it contains real data.
+func (d *Drone) WaitForState(ctx context.Context) (s State, err error) {
+	// Create context
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Handle state event before checking the state so that it can't be missed
+	states := make(chan State, 1)
+	d.onUntil(ctx, StateEvent, StateEventHandler(func(s State) {
+		select {
+		case states <- s:
+		default:
+		}
+	}))
+
+	// A state has already been received
+	d.ms.Lock()
+	received := !d.stateAt.IsZero()
+	s = *d.s
+	d.ms.Unlock()
+	if received {
+		return
+	}
+
+	// Wait
+	select {
+	case s = <-states:
+	case <-ctx.Done():
+		err = fmt.Errorf("astitello: waiting for state failed: %w", ctx.Err())
+	}
+	return
+}
+
+// StateChan returns a channel emitting states until the context is done, after which it is closed. When the
+// consumer is too slow, the latest state is dropped: the channel only buffers one state.
+func (d *Drone) StateChan(ctx context.Context) <-chan State {
+	// Create channel
+	c := make(chan State, 1)
+	send := sendUntil(ctx, func() { close(c) })
+
+	// Handle state
+	d.onUntil(ctx, StateEvent, StateEventHandler(func(s State) {
+		send(func() {
+			// Send without blocking
+			select {
+			case c <- s:
+			default:
+			}
+		})
+	}))
+	return c
+}
+
 // Close closes the drone properly
 func (d *Drone) Close() {
 	// Make sure to execute this only once
 	d.ol.Do(func() {
+		// Land
+		if d.o.landOnClose && d.Connected() {
+			d.landOnClose()
+		}
+
+		// Update connection state
+		d.mcn.Lock()
+		connected := d.connected
+		d.connected = false
+		d.mcn.Unlock()
+
+		// Dispatch before stopping the eventer so that the event is processed
+		if connected {
+			d.e.Dispatch(DisconnectEvent, nil)
+		}
+
 		// Cancel context
+		d.mcn.Lock()
 		if d.cancel != nil {
 			d.cancel()
 		}
+		d.mcn.Unlock()
 
 		// Reset once
 		d.oo = &sync.Once{}
@@ -105,10 +660,18 @@ func (d *Drone) Close() {
 		d.e.Stop()
 		d.e.Reset()
 
+		// Reset capabilities since the next session may be with another drone
+		d.resetCapabilities()
+
 		// Reset cmds
+		d.mc.Lock()
 		d.cmds = make(map[*cmd]bool)
+		d.drained = nil
+		d.shuttingDown = false
+		d.mc.Unlock()
 
 		// Close connections
+		d.mco.Lock()
 		if d.cmdConn != nil {
 			d.cmdConn.Close()
 		}
@@ -118,19 +681,126 @@ func (d *Drone) Close() {
 		if d.videoConn != nil {
 			d.videoConn.Close()
 		}
+		d.mco.Unlock()
+
+		// Wait for read goroutines to be done so that they don't overlap with the next session, but not forever
+		// in case one of them is stuck. Start refuses to start until they are done.
+		done := make(chan struct{})
+		d.mcn.Lock()
+		d.wgDone = done
+		d.mcn.Unlock()
+		go func() {
+			d.wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(closeWaitTimeout):
+			d.l.Errorf("astitello: goroutines are still running %s after closing", closeWaitTimeout)
+		}
+
+		// Reset streaming state
+		d.mvs.Lock()
+		d.stopVideoStreaming(false)
+		d.mvs.Unlock()
 	})
 }
 
-// Start starts to the drone
+// Shutdown is the graceful counterpart of Close: it stops accepting new cmds, except cancellers such as land
+// and emergency, waits for pending cmds to be done or the context to be done, and closes the drone. As with
+// Close, the drone lands first when WithLandOnClose is used.
+func (d *Drone) Shutdown(ctx context.Context) (err error) {
+	// Stop accepting new cmds
+	d.mc.Lock()
+	d.shuttingDown = true
+	drained := make(chan struct{})
+	if len(d.cmds) == 0 {
+		close(drained)
+	} else {
+		d.drained = drained
+	}
+	d.mc.Unlock()
+
+	// Wait for pending cmds
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = fmt.Errorf("astitello: waiting for pending cmds failed: %w", ctx.Err())
+	}
+
+	// Close
+	d.Close()
+	return
+}
+
+// landOnClose is best-effort: it doesn't wait more than landOnCloseTimeout for the response
+func (d *Drone) landOnClose() {
+	// Already on the ground
+	if d.FlightState() == FlightStateGrounded {
+		return
+	}
+
+	// Send cmd
+	if err := d.sendCmd(&cmd{
+		canceller: true,
+		cmd:       "land",
+		h:         d.respHandlerWithEvent(LandEvent),
+		timeout:   landOnCloseTimeout,
+	}); err != nil {
+		d.l.Error(fmt.Errorf("astitello: sending land cmd on close failed: %w", err))
+		return
+	}
+	d.setFlightState(FlightStateGrounded)
+}
+
+// Disconnect is an alias of Close
+func (d *Drone) Disconnect() {
+	d.Close()
+}
+
+// Connect is an alias of Start
+func (d *Drone) Connect() error {
+	return d.Start()
+}
+
+// Start connects to the drone
+// It returns ErrStillClosing if goroutines of the previous session are not done yet.
 func (d *Drone) Start() (err error) {
+	// Goroutines of the previous session must be done since they may still use its sockets
+	d.mcn.Lock()
+	done := d.wgDone
+	d.mcn.Unlock()
+	if done != nil {
+		select {
+		case <-done:
+		default:
+			return ErrStillClosing
+		}
+	}
+
 	// Make sure to execute this only once
 	d.oo.Do(func() {
 		// Create context
+		d.mcn.Lock()
 		d.ctx, d.cancel = context.WithCancel(context.Background())
+		d.mcn.Unlock()
 
 		// Reset once
 		d.ol = &sync.Once{}
 
+		// The drone may be connected to mid-flight
+		d.setFlightState(FlightStateUnknown)
+
+		// Reset link quality
+		d.resetLinkQuality()
+		d.abr = newBitrateController()
+
+		// Get connect deadline
+		var deadline time.Time
+		if d.o.connectTimeout > 0 {
+			deadline = time.Now().Add(d.o.connectTimeout)
+		}
+
 		// Start eventer
 		go d.e.Start(d.ctx)
 
@@ -142,39 +812,98 @@ func (d *Drone) Start() (err error) {
 
 		// Handle video
 		if err = d.handleVideo(); err != nil {
-			err = fmt.Errorf("astitello: handling video failed: %w", err)
-			return
+			// Video is required
+			if !d.o.videoOptional {
+				err = fmt.Errorf("astitello: handling video failed: %w", err)
+				return
+			}
+
+			// Continue without video
+			d.l.Error(fmt.Errorf("astitello: handling video failed, continuing without video: %w", err))
+			d.mco.Lock()
+			d.videoConn = nil
+			d.mco.Unlock()
+			err = nil
 		}
 
 		// Handle commands
-		if err = d.handleCmds(); err != nil {
+		if err = d.handleCmds(deadline); err != nil {
 			err = fmt.Errorf("astitello: handling commands failed: %w", err)
 			return
 		}
+
+		// Track link quality
+		d.startLinkQuality()
+
+		// Watch telemetry
+		if d.o.telemetryTimeout > 0 {
+			d.startTelemetryWatchdog()
+		}
+
+		// Update connection state
+		d.mcn.Lock()
+		d.connected = true
+		d.mcn.Unlock()
+
+		// Probe capabilities
+		if !d.o.manualHandshake {
+			d.updateCapabilities()
+		}
+
+		// Dispatch
+		d.e.Dispatch(ConnectEvent, nil)
 	})
 	return
 }
 
-func (d *Drone) handleState() (err error) {
+func (d *Drone) listen(addr string) (conn *net.UDPConn, err error) {
 	// Create laddr
 	var laddr *net.UDPAddr
-	if laddr, err = net.ResolveUDPAddr("udp", stateAddr); err != nil {
+	if laddr, err = net.ResolveUDPAddr(d.o.network, addr); err != nil {
 		err = fmt.Errorf("astitello: creating laddr failed: %w", err)
 		return
 	}
 
 	// Listen
-	if d.stateConn, err = net.ListenUDP("udp", laddr); err != nil {
+	if conn, err = net.ListenUDP(d.o.network, laddr); err != nil {
 		err = fmt.Errorf("astitello: listening failed: %w", err)
 		return
 	}
+	return
+}
+
+func (d *Drone) listenState() (*net.UDPConn, error) {
+	return d.listen(stateAddr)
+}
+
+func (d *Drone) handleState() (err error) {
+	// Listen
+	var conn *net.UDPConn
+	if conn, err = d.listenState(); err != nil {
+		err = fmt.Errorf("astitello: listening to state failed: %w", err)
+		return
+	}
+
+	// Update connection
+	d.mco.Lock()
+	d.stateConn = conn
+	d.mco.Unlock()
 
 	// Read state
-	go d.readState()
+	d.wg.Add(1)
+	go d.readState(conn)
 	return
 }
 
-func (d *Drone) readState() {
+func (d *Drone) readState(conn *net.UDPConn) {
+	// Make sure to signal the goroutine is done
+	defer d.wg.Done()
+
+	// The read buffer is reused since data is copied before being dispatched
+	b := make([]byte, 2048)
+	var errs int
+	cd := newCrashDetector(d.o)
+	fd := newFlightDetector(d.o)
 	for {
 		// Check context
 		if d.ctx.Err() != nil {
@@ -182,29 +911,150 @@ func (d *Drone) readState() {
 		}
 
 		// Read
-		b := make([]byte, 2048)
-		n, err := d.stateConn.Read(b)
+		n, err := conn.Read(b)
 		if err != nil {
 			if d.ctx.Err() == nil {
-				d.l.Error(fmt.Errorf("astitello: reading state failed: %w", err))
+				// Log and dispatch
+				err = fmt.Errorf("astitello: reading state failed: %w", err)
+				d.l.Error(err)
+				d.e.Dispatch(ErrorEvent, err)
+
+				// Reconnect
+				if errs++; d.shouldReconnect(errs) {
+					if conn, err = d.reconnect(&d.stateConn, d.listenState); err != nil {
+						d.l.Error(fmt.Errorf("astitello: reconnecting state failed: %w", err))
+						return
+					}
+					errs = 0
+					continue
+				}
+
+				// Make sure not to spin when the connection is in a persistent error state
+				d.sleep(readErrorSleep(errs))
 			}
 			continue
 		}
+		errs = 0
 
-		// Create state
-		s, err := newState(string(bytes.TrimSpace(b[:n])))
-		if err != nil {
-			d.l.Error(fmt.Errorf("astitello: creating state failed: %w", err))
-			continue
-		}
+		// Record
+		d.record(RecordTypeState, b[:n])
 
-		// Update state
+		// Handle state
+		d.handleRawState(string(bytes.TrimSpace(b[:n])), cd, fd)
+	}
+}
+
+func (d *Drone) handleRawState(raw string, cd *crashDetector, fd *flightDetector) {
+	// The drone occasionally sends empty datagrams, which are not worth an error
+	if raw == "" {
+		return
+	}
+
+	// Update raw state before parsing it so that invalid states can be debugged
+	d.ms.Lock()
+	d.rawState = raw
+	d.ms.Unlock()
+
+	// Create state
+	s, err := newState(raw)
+	if err != nil {
+		d.logStateError(fmt.Errorf("astitello: creating state failed: %w", err))
+		return
+	}
+
+	// Update state
+	d.ms.Lock()
+	d.activity = classifyActivity(*d.s, s, !d.stateAt.IsZero(), d.fs)
+	*d.s = s
+	d.stateAt = time.Now()
+	d.ms.Unlock()
+
+	// Dispatch
+	d.e.Dispatch(StateEvent, s)
+
+	// Detect crashes before landings since crashes are detected while airborne
+	if cd.update(s, d.FlightState() == FlightStateAirborne, time.Now()) {
+		d.l.Errorf("astitello: crash detected")
+		d.e.Dispatch(CrashEvent, s)
+	}
+
+	// Detect take offs and landings
+	if name := fd.update(s); name != "" {
+		if name == AirborneEvent {
+			d.setFlightState(FlightStateAirborne)
+		} else {
+			d.setFlightState(FlightStateGrounded)
+		}
+		d.e.Dispatch(name, nil)
+	} else if fd.grounded() {
+		// The drone was on the ground when connecting
 		d.ms.Lock()
-		*d.s = s
+		if d.fs == FlightStateUnknown {
+			d.fs = FlightStateGrounded
+		}
 		d.ms.Unlock()
+	}
+}
 
-		// Dispatch
-		d.e.Dispatch(StateEvent, s)
+// logStateError logs invalid states at most once per stateErrorInterval so that a firmware sending them
+// at 10Hz doesn't flood logs, and reports how many have been skipped
+func (d *Drone) logStateError(err error) {
+	// Lock
+	d.ms.Lock()
+	defer d.ms.Unlock()
+
+	// Error has been logged recently
+	if !d.stateErrAt.IsZero() && time.Since(d.stateErrAt) < stateErrorInterval {
+		d.stateErrs++
+		return
+	}
+
+	// Log
+	if d.stateErrs > 0 {
+		err = fmt.Errorf("%w (%d similar errors skipped)", err, d.stateErrs)
+	}
+	d.l.Error(err)
+
+	// Update
+	d.stateErrAt = time.Now()
+	d.stateErrs = 0
+}
+
+func readErrorSleep(errs int) (d time.Duration) {
+	if d = time.Duration(errs) * readErrorBackoff; d > readErrorMaxSleep {
+		d = readErrorMaxSleep
+	}
+	return
+}
+
+// sleep waits for the duration or until the context is done
+func (d *Drone) sleep(dur time.Duration) {
+	select {
+	case <-time.After(dur):
+	case <-d.ctx.Done():
+	}
+}
+
+// CommandEventHandler returns the proper EventHandler for the Command event
+// It is dispatched once a cmd is done, whatever its outcome, except when the drone is not connected
+func CommandEventHandler(f func(r CommandResult)) astikit.EventerHandler {
+	return func(payload interface{}) {
+		f(payload.(CommandResult))
+	}
+}
+
+// ErrorEventHandler returns the proper EventHandler for the Error event
+func ErrorEventHandler(f func(err error)) astikit.EventerHandler {
+	return func(payload interface{}) {
+		f(payload.(error))
+	}
+}
+
+// ResponseEventHandler returns the proper EventHandler for the Response event
+// It is dispatched for every response received, before it is matched to a cmd
+func ResponseEventHandler(f func(resp string)) astikit.EventerHandler {
+	return func(payload interface{}) {
+		f(payload.(string))
 	}
 }
 
@@ -215,62 +1065,246 @@ func StateEventHandler(f func(s State)) astikit.EventerHandler {
 	}
 }
 
-func (d *Drone) handleVideo() (err error) {
-	// Create laddr
-	var laddr *net.UDPAddr
-	if laddr, err = net.ResolveUDPAddr("udp", videoAddr); err != nil {
-		err = fmt.Errorf("astitello: creating laddr failed: %w", err)
-		return
+// WifiChangedEventHandler returns the proper EventHandler for the WifiChanged event
+func WifiChangedEventHandler(f func(ssid string)) astikit.EventerHandler {
+	return func(payload interface{}) {
+		f(payload.(string))
+	}
+}
+
+func (d *Drone) listenVideo() (conn *net.UDPConn, err error) {
+	// Listen
+	if conn, err = d.listen(videoAddr); err != nil {
+		return
+	}
+
+	// Set socket read buffer size
+	if d.o.videoReadBufferSize > 0 {
+		if err = conn.SetReadBuffer(d.o.videoReadBufferSize); err != nil {
+			conn.Close()
+			err = fmt.Errorf("astitello: setting read buffer failed: %w", err)
+			return
+		}
+	}
+	return
+}
+
+func (d *Drone) handleVideo() (err error) {
+	// Listen
+	var conn *net.UDPConn
+	if conn, err = d.listenVideo(); err != nil {
+		err = fmt.Errorf("astitello: listening to video failed: %w", err)
+		return
+	}
+
+	// Update connection
+	d.mco.Lock()
+	d.videoConn = conn
+	d.mco.Unlock()
+
+	// Reset stats
+	d.resetVideoStats()
+
+	// Create frame parser
+	d.vfp = nil
+	if d.o.videoFrames || d.o.videoDecoder != nil {
+		d.vfp = newVideoFrameParser()
+	}
+
+	// Start decoder
+	d.vd = nil
+	if d.o.videoDecoder != nil {
+		d.vd = newVideoDecoder(d.o.videoDecoder, videoDecoderSize)
+		d.vd.dropped = &d.vs.DecoderDropped
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.vd.start(d.ctx, func(i image.Image) { d.e.Dispatch(VideoImageEvent, i) }, d.l)
+		}()
+	}
+
+	// Start buffer
+	d.vb = nil
+	if d.o.videoBufferSize > 0 {
+		d.vb = newVideoBuffer(d.o.videoBufferSize, d.o.videoDropPolicy)
+		d.vb.dropped = &d.vs.BufferDropped
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.vb.start(d.ctx, d.e)
+		}()
+	}
+
+	// Read video
+	d.wg.Add(1)
+	go d.readVideo(conn)
+
+	// Dispatch stats
+	d.startVideoStats()
+	return
+}
+
+func (d *Drone) readVideo(conn *net.UDPConn) {
+	// Make sure to signal the goroutine is done
+	defer d.wg.Done()
+
+	// The read buffer is reused since data is copied before being dispatched
+	b := make([]byte, d.o.videoReadSize)
+	var buf []byte
+	var errs int
+	for {
+		// Check context
+		if d.ctx.Err() != nil {
+			return
+		}
+
+		// Discard the pending packet when the stream has been stopped or restarted
+		buf = d.resetVideoIfNeeded(buf)
+
+		// When a packet is pending, we can't rely on its size only to know whether it's over since its last
+		// datagram may be exactly MTU bytes long. Therefore we make sure it gets flushed if no datagram
+		// is received in time
+		if len(buf) > 0 {
+			conn.SetReadDeadline(time.Now().Add(videoFlushTimeout))
+		} else {
+			conn.SetReadDeadline(time.Time{})
+		}
+
+		// Read
+		n, err := conn.Read(b)
+		if err != nil {
+			// Flush on timeout
+			var nerr net.Error
+			if errors.As(err, &nerr) && nerr.Timeout() {
+				buf = d.dispatchVideoPacket(buf)
+				continue
+			}
+
+			if d.ctx.Err() == nil {
+				// Log and dispatch
+				err = fmt.Errorf("astitello: reading video failed: %w", err)
+				d.l.Error(err)
+				d.e.Dispatch(ErrorEvent, err)
+
+				// Reconnect
+				if errs++; d.shouldReconnect(errs) {
+					if conn, err = d.reconnect(&d.videoConn, d.listenVideo); err != nil {
+						d.l.Error(fmt.Errorf("astitello: reconnecting video failed: %w", err))
+						return
+					}
+					errs = 0
+					continue
+				}
+
+				// Make sure not to spin when the connection is in a persistent error state
+				d.sleep(readErrorSleep(errs))
+			}
+			continue
+		}
+		errs = 0
+
+		// Update stats
+		atomic.AddUint64(&d.vs.Bytes, uint64(n))
+		atomic.AddUint64(&d.vs.Datagrams, 1)
+
+		// Signal video has started and reset idle detection
+		d.videoReceived()
+
+		// The stream may have been stopped or restarted while waiting for the datagram
+		buf = d.resetVideoIfNeeded(buf)
+
+		// Handle datagram
+		buf = d.handleVideoDatagram(buf, b[:n])
+	}
+}
+
+// handleVideoDatagram reassembles video packets from datagrams, dispatches them once they're complete and
+// returns the pending packet
+func (d *Drone) handleVideoDatagram(buf, b []byte) []byte {
+	// Packets start with a start code, which means the pending packet is over
+	if bytes.HasPrefix(b, videoStartCode) {
+		d.videoOrphan = false
+		buf = d.dispatchVideoPacket(buf)
+	} else if len(buf) == 0 && !d.videoOrphan {
+		// A datagram that doesn't start a packet while no packet is pending means the previous ones were lost.
+		// Following datagrams until the next start code belong to the same packet, which is counted once.
+		d.videoOrphan = true
+		atomic.AddUint64(&d.vs.Dropped, 1)
+	}
+
+	// Append to buffer
+	buf = append(buf, b...)
+
+	// Packet is not over
+	if len(b) == d.o.videoMTU {
+		return buf
+	}
+
+	// Dispatch
+	return d.dispatchVideoPacket(buf)
+}
+
+// resetVideo makes the video goroutine discard its pending packet so that stale bytes don't leak into the
+// next stream
+func (d *Drone) resetVideo() {
+	atomic.StoreInt32(&d.videoReset, 1)
+}
+
+func (d *Drone) resetVideoIfNeeded(buf []byte) []byte {
+	// No reset needed
+	if !atomic.CompareAndSwapInt32(&d.videoReset, 1, 0) {
+		return buf
 	}
 
-	// Listen
-	if d.videoConn, err = net.ListenUDP("udp", laddr); err != nil {
-		err = fmt.Errorf("astitello: listening failed: %w", err)
-		return
+	// Reset frame parser
+	if d.vfp != nil {
+		d.vfp = newVideoFrameParser()
 	}
 
-	// Read video
-	go d.readVideo()
-	return
+	// Forget about the previous stream
+	d.videoOrphan = false
+	return buf[:0]
 }
 
-func (d *Drone) readVideo() {
-	var buf []byte
-	var bufLength int
-	for {
-		// Check context
-		if d.ctx.Err() != nil {
-			return
-		}
+func (d *Drone) dispatchVideoPacket(buf []byte) []byte {
+	// Nothing to dispatch
+	if len(buf) == 0 {
+		return buf
+	}
 
-		// Read
-		b := make([]byte, 2048)
-		n, err := d.videoConn.Read(b)
-		if err != nil {
-			if d.ctx.Err() == nil {
-				d.l.Error(fmt.Errorf("astitello: reading video failed: %w", err))
-			}
-			continue
-		}
+	// Record
+	d.record(RecordTypeVideo, buf)
 
-		// Append to buffer
-		buf = append(buf, b[:n]...)
-		bufLength += n
+	// Update stats
+	atomic.AddUint64(&d.vs.Packets, 1)
 
-		// Packet is not over
-		if n == 1460 {
-			continue
-		}
+	// Dispatch packet
+	if d.o.videoPackets {
+		p := make([]byte, len(buf))
+		copy(p, buf)
+		d.dispatchVideo(VideoPacketEvent, p)
+	}
 
-		// Dispatch
-		p := make([]byte, bufLength)
-		copy(p, buf[:bufLength])
-		d.e.Dispatch(VideoPacketEvent, p)
+	// Handle frames
+	if d.vfp != nil {
+		for _, f := range d.vfp.parse(buf) {
+			// Update stats
+			atomic.AddUint64(&d.vs.Frames, 1)
+
+			// Dispatch
+			if d.o.videoFrames {
+				d.dispatchVideo(VideoFrameEvent, f)
+			}
 
-		// Reset buffer
-		buf = buf[:0]
-		bufLength = 0
+			// Decode
+			if d.vd != nil {
+				d.vd.add(f)
+			}
+		}
 	}
+
+	// Reset buffer
+	return buf[:0]
 }
 
 // VideoPacketEventHandler returns the proper EventHandler for the VideoPacket event
@@ -280,39 +1314,83 @@ func VideoPacketEventHandler(f func(p []byte)) astikit.EventerHandler {
 	}
 }
 
-func (d *Drone) handleCmds() (err error) {
+func (d *Drone) dialCmd() (*net.UDPConn, error) {
+	// Get addr
+	d.mco.Lock()
+	addr := d.cmdAddr()
+	d.mco.Unlock()
+
+	// Dial
+	return d.dialCmdAddr(addr)
+}
+
+// cmdAddr returns the address cmds are sent to. mco must be locked.
+func (d *Drone) cmdAddr() string {
+	if d.raddr != "" {
+		return d.raddr
+	} else if d.o.cmdAddr != "" {
+		return d.o.cmdAddr
+	}
+	return cmdAddr
+}
+
+func (d *Drone) dialCmdAddr(addr string) (conn *net.UDPConn, err error) {
 	// Create raddr
 	var raddr *net.UDPAddr
-	if raddr, err = net.ResolveUDPAddr("udp", cmdAddr); err != nil {
+	if raddr, err = net.ResolveUDPAddr(d.o.network, addr); err != nil {
 		err = fmt.Errorf("astitello: creating raddr failed: %w", err)
 		return
 	}
 
 	// Create laddr
 	var laddr *net.UDPAddr
-	if laddr, err = net.ResolveUDPAddr("udp", respAddr); err != nil {
+	if laddr, err = net.ResolveUDPAddr(d.o.network, respAddr); err != nil {
 		err = fmt.Errorf("astitello: creating laddr failed: %w", err)
 		return
 	}
 
 	// Dial
-	if d.cmdConn, err = net.DialUDP("udp", laddr, raddr); err != nil {
+	if conn, err = net.DialUDP(d.o.network, laddr, raddr); err != nil {
 		err = fmt.Errorf("astitello: dialing failed: %w", err)
 		return
 	}
+	return
+}
+
+func (d *Drone) handleCmds(deadline time.Time) (err error) {
+	// Dial
+	var conn *net.UDPConn
+	if conn, err = d.dialCmd(); err != nil {
+		err = fmt.Errorf("astitello: dialing cmd failed: %w", err)
+		return
+	}
+
+	// Update connection
+	d.mco.Lock()
+	d.cmdConn = conn
+	d.mco.Unlock()
 
 	// Read responses
-	go d.readResponses()
+	d.wg.Add(1)
+	go d.readResponses(conn)
 
 	// Command
-	if err = d.command(); err != nil {
-		err = fmt.Errorf("astitello: command failed: %w", err)
-		return
+	if !d.o.manualHandshake {
+		if err = d.command(deadline); err != nil {
+			err = fmt.Errorf("astitello: command failed: %w", err)
+			return
+		}
 	}
 	return
 }
 
-func (d *Drone) readResponses() {
+func (d *Drone) readResponses(conn *net.UDPConn) {
+	// Make sure to signal the goroutine is done
+	defer d.wg.Done()
+
+	// The read buffer is reused since data is copied before being dispatched
+	b := make([]byte, 2048)
+	var errs int
 	for {
 		// Check context
 		if d.ctx.Err() != nil {
@@ -320,24 +1398,182 @@ func (d *Drone) readResponses() {
 		}
 
 		// Read
-		b := make([]byte, 2048)
-		n, err := d.cmdConn.Read(b)
+		n, err := conn.Read(b)
 		if err != nil {
+			// Connection has been replaced by Reconnect()
+			if c := d.replacedCmdConn(conn); c != nil {
+				conn = c
+				errs = 0
+				continue
+			}
+
 			if d.ctx.Err() == nil {
-				d.l.Error(fmt.Errorf("astitello: reading response failed: %w", err))
+				// Log and dispatch
+				err = fmt.Errorf("astitello: reading response failed: %w", err)
+				d.l.Error(err)
+				d.e.Dispatch(ErrorEvent, err)
+
+				// Reconnect
+				if errs++; d.shouldReconnect(errs) {
+					if conn, err = d.reconnect(&d.cmdConn, d.dialCmd); err != nil {
+						d.l.Error(fmt.Errorf("astitello: reconnecting cmd failed: %w", err))
+						return
+					}
+					errs = 0
+					continue
+				}
+
+				// Make sure not to spin when the connection is in a persistent error state
+				d.sleep(readErrorSleep(errs))
 			}
 			continue
 		}
+		errs = 0
 
 		// Log
-		r := bytes.TrimSpace(b[:n])
+		r := string(bytes.TrimSpace(b[:n]))
 		d.l.Debugf("astitello: received resp '%s'", r)
 
-		// Signal
-		d.rc.L.Lock()
-		d.lr = string(r)
-		d.rc.Signal()
-		d.rc.L.Unlock()
+		// Dispatch
+		d.e.Dispatch(ResponseEvent, r)
+
+		// Send response to waiting cmd
+		if !d.respondWaitingCmd(r) {
+			d.l.Debugf("astitello: no cmd waiting for resp '%s'", r)
+		}
+	}
+}
+
+// Responses don't reference the cmd they answer, therefore we use their format to find the proper cmd:
+// "ok" is expected by regular cmds whereas values are expected by query cmds. "error" can answer any cmd.
+// Among cmds expecting the same format, the first one sent is picked. If no cmd expects this format, the first
+// one sent is picked as well. Only cmds with attempts whose response hasn't been received yet are considered.
+// The response is sent while locked so that it can't be sent once the cmd is done.
+func (d *Drone) respondWaitingCmd(resp string) (ok bool) {
+	// Lock
+	d.mw.Lock()
+	defer d.mw.Unlock()
+
+	// Remove abandoned cmds whose responses can't be expected anymore
+	now := time.Now()
+	for _, v := range append([]*cmd{}, d.waiting...) {
+		if !v.abandonedUntil.IsZero() && now.After(v.abandonedUntil) {
+			d.removeWaitingCmdUnlocked(v)
+		}
+	}
+
+	// Get cmd
+	var c *cmd
+	query := resp != "ok"
+	for _, v := range d.waiting {
+		// No response is expected
+		if v.pending == 0 {
+			continue
+		}
+
+		// Errors answer any cmd, and the first cmd is picked if none expects this format
+		if c == nil {
+			c = v
+		}
+		if !strings.HasPrefix(resp, "error") && v.isQuery() == query {
+			c = v
+			break
+		}
+	}
+
+	// No waiting cmd
+	if c == nil {
+		return
+	}
+
+	// The cmd has been abandoned but the same cmd has been sent again since, e.g. when connecting: responses are
+	// interchangeable and the live one gets it
+	if !c.abandonedUntil.IsZero() {
+		for _, v := range d.waiting {
+			if v.abandonedUntil.IsZero() && v.pending > 0 && v.cmd == c.cmd {
+				c = v
+				break
+			}
+		}
+	}
+
+	// The cmd has been abandoned: drop the response
+	if !c.abandonedUntil.IsZero() {
+		d.l.Debugf("astitello: cmd=%q dropping late resp '%s'", c.cmd, resp)
+		if c.pending--; c.pending == 0 {
+			d.removeWaitingCmdUnlocked(c)
+		}
+		return true
+	}
+
+	// Send response. The channel is sized for all attempts, therefore this never blocks.
+	c.pending--
+	select {
+	case c.resp <- resp:
+	default:
+	}
+	return true
+}
+
+func (d *Drone) addWaitingCmd(c *cmd, attempts int) {
+	d.mw.Lock()
+	defer d.mw.Unlock()
+	c.resp = make(chan string, attempts)
+	d.waiting = append(d.waiting, c)
+}
+
+// addPendingResp records that an attempt of the cmd has been written and expects a response
+func (d *Drone) addPendingResp(c *cmd) {
+	d.mw.Lock()
+	defer d.mw.Unlock()
+	c.pending++
+}
+
+// pendingResps returns the number of attempts of the cmd whose response hasn't been received yet
+func (d *Drone) pendingResps(c *cmd) int {
+	d.mw.Lock()
+	defer d.mw.Unlock()
+	return c.pending
+}
+
+// removeWaitingCmd removes the cmd from the cmds waiting for a response
+// If the drone may still respond to some of its attempts, the cmd is abandoned instead: it stays in line for
+// its timeout so that late responses are dropped rather than handed to the next cmds.
+func (d *Drone) removeWaitingCmd(c *cmd, abandon bool) {
+	// Lock
+	d.mw.Lock()
+	defer d.mw.Unlock()
+
+	// Abandon
+	if abandon && c.pending > 0 && c.timeout > 0 {
+		c.abandonedUntil = time.Now().Add(c.timeout)
+		return
+	}
+
+	// Remove
+	d.removeWaitingCmdUnlocked(c)
+}
+
+// removeAbandonedCmds removes abandoned cmds regardless of whether their responses can still be expected
+func (d *Drone) removeAbandonedCmds() {
+	// Lock
+	d.mw.Lock()
+	defer d.mw.Unlock()
+
+	// Remove
+	for _, v := range append([]*cmd{}, d.waiting...) {
+		if !v.abandonedUntil.IsZero() {
+			d.removeWaitingCmdUnlocked(v)
+		}
+	}
+}
+
+func (d *Drone) removeWaitingCmdUnlocked(c *cmd) {
+	for idx, v := range d.waiting {
+		if v == c {
+			d.waiting = append(d.waiting[:idx:idx], d.waiting[idx+1:]...)
+			return
+		}
 	}
 }
 
@@ -346,7 +1582,7 @@ type respHandler func(resp string) error
 func defaultRespHandler(resp string) (err error) {
 	// Check response
 	if resp != "ok" {
-		err = fmt.Errorf("astitello: invalid response: %w", errors.New(resp))
+		err = fmt.Errorf("astitello: invalid response: %w", &DroneError{Raw: resp})
 		return
 	}
 	return
@@ -366,12 +1602,27 @@ func (d *Drone) respHandlerWithEvent(name string) respHandler {
 }
 
 type cmd struct {
-	canceller bool
-	cmd       string
-	h         respHandler
-	timeout   time.Duration
+	abandonedUntil time.Time // Late responses are dropped until then, locked by Drone.mw
+	canceller      bool
+	cmd            string
+	ctx            context.Context // Optional context provided by the caller
+	h              respHandler
+	pending        int // Number of attempts whose response hasn't been received yet, locked by Drone.mw
+	resp           chan string
+	sentAt         time.Time // When the cmd was last written
+	timeout        time.Duration
+}
+
+func (c *cmd) isQuery() bool {
+	return strings.HasSuffix(c.cmd, "?")
 }
 
+// priorityCmd returns whether the cmd can be sent without waiting for the previous cmd to be done, which is the
+// case of cancellers, e.g. land, unless another canceller is running.
+// Since responses don't reference the cmd they answer, they are matched by format and order only: while a
+// priority cmd overlaps a cmd expecting the same format, the first response is handed to the cmd sent first.
+// For instance, if the drone answers a land sent during a move first, the move gets the land's "ok", and an
+// error answering the land is returned by the move.
 func (d *Drone) priorityCmd(cmd *cmd) (priority bool) {
 	// Lock
 	d.mc.Lock()
@@ -396,19 +1647,53 @@ func (d *Drone) priorityCmd(cmd *cmd) (priority bool) {
 	return
 }
 
+// sendCmd sends the cmd and waits for its response
+// Cmds are sent one at a time, except priority cmds, see priorityCmd for the limitations of overlapping cmds.
 func (d *Drone) sendCmd(cmd *cmd) (err error) {
+	// Log and dispatch outcome, and make sure the cmd can be retrieved from the error
+	start := time.Now()
+	var priority bool
+	defer func() {
+		elapsed := time.Since(start)
+		d.logCmd(cmd, priority, elapsed, err)
+		if !errors.Is(err, ErrNotConnected) {
+			r := CommandResult{
+				Command: cmd.cmd,
+				Elapsed: elapsed,
+				Err:     err,
+			}
+			if err != nil {
+				r.Error = err.Error()
+			}
+			d.e.Dispatch(CommandEvent, r)
+		}
+		if err != nil {
+			err = &CommandError{Command: cmd.cmd, Err: err}
+		}
+	}()
+
+	// Get connection
+	d.mco.Lock()
+	conn := d.cmdConn
+	d.mco.Unlock()
+
 	// No connection
-	if d.cmdConn == nil {
+	if conn == nil {
 		err = ErrNotConnected
 		return
 	}
 
 	// In most cases we need to wait for the previous cmd to be done. But not when this is a priority cmd.
 	// This is a priority cmd if cmd is a canceller and no other canceller is running
-	priority := d.priorityCmd(cmd)
+	priority = d.priorityCmd(cmd)
 
 	// Add cmd
 	d.mc.Lock()
+	if d.shuttingDown && !cmd.canceller {
+		d.mc.Unlock()
+		err = ErrShuttingDown
+		return
+	}
 	d.cmds[cmd] = true
 	d.mc.Unlock()
 
@@ -416,6 +1701,10 @@ func (d *Drone) sendCmd(cmd *cmd) (err error) {
 	defer func() {
 		d.mc.Lock()
 		delete(d.cmds, cmd)
+		if d.drained != nil && len(d.cmds) == 0 {
+			close(d.drained)
+			d.drained = nil
+		}
 		d.mc.Unlock()
 	}()
 
@@ -431,100 +1720,499 @@ func (d *Drone) sendCmd(cmd *cmd) (err error) {
 		defer d.msc.Unlock()
 	}
 
-	// Lock resp
-	d.rc.L.Lock()
-	defer d.rc.L.Unlock()
+	// Wait for responses. The cmd keeps waiting across attempts so that the late response of a timed out attempt
+	// is not mistaken for the response of another cmd.
+	if cmd.h != nil {
+		d.addWaitingCmd(cmd, d.o.commandRetries+1)
+		defer func() {
+			// Only the drone can still respond when it's been too slow or the caller has given up, and only if the
+			// connection hasn't been replaced in the meantime
+			d.removeWaitingCmd(cmd, (errors.Is(err, ErrTimeout) || (cmd.ctx != nil && cmd.ctx.Err() != nil)) &&
+				d.replacedCmdConn(conn) == nil)
+		}()
+	}
+
+	// Loop through attempts
+	for attempt := 0; ; attempt++ {
+		// Write cmd
+		if err = d.writeCmd(conn, cmd); err == nil {
+			return
+		}
+
+		// Only timeouts of cmds that are safe to send twice are retried
+		if attempt >= d.o.commandRetries || !errors.Is(err, ErrTimeout) || !retriableCmd(cmd.cmd) {
+			return
+		}
+		d.l.Debugf("astitello: cmd=%q attempt=%d outcome=timeout retrying", cmd.cmd, attempt+1)
+	}
+}
+
+// logCmd logs the outcome of a cmd as key=value pairs so that logs can be grepped. Elapsed includes the time
+// spent waiting for previous cmds to be done and retries. Failures are logged as errors, except when they are
+// caused by the drone being closed or the caller giving up.
+func (d *Drone) logCmd(c *cmd, priority bool, elapsed time.Duration, err error) {
+	// Get outcome
+	outcome := "ok"
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrTimeout):
+			outcome = "timeout"
+		case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded), errors.Is(err, ErrShuttingDown):
+			outcome = "canceled"
+		default:
+			outcome = "error"
+		}
+	}
+
+	// Log
+	const format = "astitello: cmd=%q priority=%t canceller=%t elapsed=%s outcome=%s"
+	switch outcome {
+	case "ok":
+		d.l.Debugf(format, c.cmd, priority, c.canceller, elapsed, outcome)
+	case "canceled":
+		d.l.Debugf(format+" error=%q", c.cmd, priority, c.canceller, elapsed, outcome, err)
+	default:
+		d.l.Errorf(format+" error=%q", c.cmd, priority, c.canceller, elapsed, outcome, err)
+	}
+}
+
+// retriableCmd returns whether a cmd can be sent again when its response hasn't been received. Movement cmds
+// are retriable since a response is more often lost than the cmd itself, but the drone may move twice. Cmds
+// that can't be undone, such as take off, land, flip or emergency, are never retried. The "command" cmd has
+// its own retry logic.
+func retriableCmd(c string) bool {
+	// Queries are always retriable
+	if strings.HasSuffix(c, "?") {
+		return true
+	}
+
+	// Get name
+	name := c
+	if i := strings.Index(c, " "); i > -1 {
+		name = c[:i]
+	}
+
+	// Switch on name
+	switch name {
+	case "back", "ccw", "curve", "cw", "down", "downvision", "forward", "go", "left", "right", "setbitrate",
+		"setfps", "setresolution", "speed", "stop", "streamoff", "streamon", "up":
+		return true
+	}
+	return false
+}
+
+// writeCmd writes an attempt of the cmd and waits for its response
+// When a previous attempt has timed out, its late response may be received as well: the cmd then waits for the
+// response of every attempt, or for the attempt's timeout if some of them are lost, and the last response wins.
+// This makes sure the cmd is not reported as done while the drone is still executing the attempt, and that the
+// extra response is not handed to the next cmd.
+func (d *Drone) writeCmd(conn *net.UDPConn, cmd *cmd) (err error) {
+	// Check caller context
+	var done <-chan struct{}
+	if cmd.ctx != nil {
+		if err = cmd.ctx.Err(); err != nil {
+			return
+		}
+		done = cmd.ctx.Done()
+	}
+
+	// Log
+	d.l.Debugf("astitello: cmd=%q sending", cmd.cmd)
+
+	// Expect a response. Make sure to do it before writing so that the response can't be missed.
+	if cmd.h != nil {
+		d.addPendingResp(cmd)
+	}
+
+	// Write
+	cmd.sentAt = time.Now()
+	if _, err = conn.Write([]byte(cmd.cmd)); err != nil {
+		err = fmt.Errorf("astitello: writing failed: %w", &TransportError{Err: err})
+		return
+	}
+
+	// Record
+	d.record(RecordTypeCmd, []byte(cmd.cmd))
+
+	// No handler
+	if cmd.h == nil {
+		return
+	}
+
+	// Create context
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if cmd.timeout > 0 {
+		ctx, cancel = context.WithTimeout(d.ctx, cmd.timeout)
+	} else {
+		ctx, cancel = context.WithCancel(d.ctx)
+	}
+	defer cancel()
+
+	// Wait for responses
+	var resp string
+	var received bool
+wait:
+	for !received || d.pendingResps(cmd) > 0 {
+		select {
+		case resp = <-cmd.resp:
+			received = true
+		case <-done:
+			err = cmd.ctx.Err()
+			return
+		case <-ctx.Done():
+			// The responses of previous attempts are lost
+			if received && d.ctx.Err() == nil {
+				break wait
+			}
+
+			// Only the cmd's timeout is reported as such, not the drone being closed
+			if err = ctx.Err(); errors.Is(err, context.DeadlineExceeded) && d.ctx.Err() == nil {
+				err = fmt.Errorf("astitello: no response after %s: %w", cmd.timeout, ErrTimeout)
+			}
+			return
+		}
+	}
+
+	// Update link quality
+	d.updateRTT(time.Since(cmd.sentAt))
+
+	// Drone responded with an error
+	if strings.HasPrefix(resp, "error") {
+		err = &DroneError{Raw: resp}
+		return
+	}
+
+	// Custom
+	if err = cmd.h(resp); err != nil {
+		err = fmt.Errorf("astitello: custom handler failed: %w", err)
+		return
+	}
+	return
+}
+
+// EnterSDKMode sends the "command" handshake, which makes the drone accept SDK cmds. Start() already does it
+// unless WithManualHandshake is used, but it can be run again, e.g. after the drone has rebooted. Retries and
+// timeout are configured with WithConnectRetries and WithConnectTimeout. Capabilities are then probed unless
+// they already have been.
+func (d *Drone) EnterSDKMode() (err error) {
+	// Get deadline
+	var deadline time.Time
+	if d.o.connectTimeout > 0 {
+		deadline = time.Now().Add(d.o.connectTimeout)
+	}
+
+	// Command
+	if err = d.command(deadline); err != nil {
+		err = fmt.Errorf("astitello: command failed: %w", err)
+		return
+	}
+
+	// Probe capabilities
+	d.updateCapabilities()
+	return
+}
+
+// command sends the "command" cmd, which enters SDK mode. A zero deadline means no deadline.
+func (d *Drone) command(deadline time.Time) (err error) {
+	// Get attempts
+	attempts, timeout := 1, d.o.timeouts.fallback()
+	if d.o.connectRetries > 0 {
+		attempts = d.o.connectRetries
+		if d.o.connectRetryTimeout > 0 {
+			timeout = d.o.connectRetryTimeout
+		}
+	}
+
+	// Loop through attempts
+	for attempt := 1; attempt <= attempts; attempt++ {
+		// Make sure not to wait past the deadline
+		t := timeout
+		if !deadline.IsZero() {
+			left := time.Until(deadline)
+			if left <= 0 {
+				err = fmt.Errorf("astitello: connecting took more than %s: %w", d.o.connectTimeout, ErrTimeout)
+				return
+			} else if left < t {
+				t = left
+			}
+		}
+
+		// Send "command" cmd
+		if err = d.sendCmd(&cmd{
+			cmd:     "command",
+			h:       defaultRespHandler,
+			timeout: t,
+		}); err == nil {
+			return
+		}
+		err = fmt.Errorf("astitello: sending 'command' cmd failed: %w", err)
+
+		// Deadline exceeded
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			err = fmt.Errorf("astitello: connecting took more than %s: %w", d.o.connectTimeout, err)
+			return
+		}
+
+		// Only timeouts are retried: a freshly powered drone often ignores the first cmds
+		if !errors.Is(err, ErrTimeout) {
+			return
+		}
+		d.l.Debugf("astitello: attempt %d/%d of 'command' cmd timed out", attempt, attempts)
+	}
+	return
+}
+
+// StartVideo makes Tello start streaming video
+func (d *Drone) StartVideo() error {
+	return d.StartVideoContext(context.Background())
+}
+
+// StartVideoContext makes Tello start streaming video and stops waiting for the response when the context is
+// done. When WithVideoStartTimeout is used, it also makes sure video datagrams are received in time since the
+// drone sometimes acknowledges streamon without streaming anything.
+func (d *Drone) StartVideoContext(ctx context.Context) (err error) {
+	// Video is not available
+	d.mco.Lock()
+	unavailable := d.videoConn == nil
+	d.mco.Unlock()
+	if unavailable && d.Connected() {
+		err = ErrVideoUnavailable
+		return
+	}
+
+	// Discard leftovers of a previous stream
+	d.resetVideo()
+
+	// Wait for the first datagram before sending the cmd so that it can't be missed
+	started := d.waitVideoStarted()
+
+	// Send cmd
+	if err = d.sendCmd(&cmd{
+		cmd:     "streamon",
+		ctx:     ctx,
+		h:       defaultRespHandler,
+		timeout: d.o.timeouts.fallback(),
+	}); err != nil {
+		err = fmt.Errorf("astitello: sending streamon cmd failed: %w", err)
+		return
+	}
+
+	// No need to wait for video
+	if d.o.videoStartTimeout <= 0 {
+		return
+	}
+
+	// Wait for video
+	t := time.NewTimer(d.o.videoStartTimeout)
+	defer t.Stop()
+	select {
+	case <-started:
+	case <-t.C:
+		err = fmt.Errorf("astitello: no video received %s after streamon: %w", d.o.videoStartTimeout, ErrTimeout)
+	case <-ctx.Done():
+		err = fmt.Errorf("astitello: waiting for video failed: %w", ctx.Err())
+	}
+	return
+}
+
+// waitVideoStarted returns a channel closed once the next video datagram has been received
+func (d *Drone) waitVideoStarted() <-chan struct{} {
+	d.mvs.Lock()
+	defer d.mvs.Unlock()
+	if d.videoStarted == nil {
+		d.videoStarted = make(chan struct{})
+	}
+	return d.videoStarted
+}
+
+// videoReceived is called each time a video datagram is received
+func (d *Drone) videoReceived() {
+	// Lock
+	d.mvs.Lock()
+	defer d.mvs.Unlock()
+
+	// Signal StartVideo
+	if d.videoStarted != nil {
+		close(d.videoStarted)
+		d.videoStarted = nil
+	}
+
+	// Update time
+	d.videoAt = time.Now()
+
+	// Already streaming
+	if d.videoOn {
+		d.videoIdle.Reset(d.o.videoIdleTimeout)
+		return
+	}
+
+	// Update state
+	d.videoOn = true
+	d.videoIdle = time.AfterFunc(d.o.videoIdleTimeout, d.videoIdled)
+
+	// Dispatch
+	d.e.Dispatch(VideoStartedEvent, nil)
+}
 
-	// Log
-	d.l.Debugf("astitello: sending cmd '%s'", cmd.cmd)
+func (d *Drone) videoIdled() {
+	// Lock
+	d.mvs.Lock()
+	defer d.mvs.Unlock()
 
-	// Write
-	if _, err = d.cmdConn.Write([]byte(cmd.cmd)); err != nil {
-		err = fmt.Errorf("astitello: writing failed: %w", err)
+	// A datagram has been received while the timer was firing, in which case it has been reset
+	if !d.videoOn || time.Since(d.videoAt) < d.o.videoIdleTimeout {
 		return
 	}
 
-	// No handler
-	if cmd.h == nil {
+	// Stop
+	d.stopVideoStreaming(true)
+}
+
+// stopVideoStreaming assumes mvs is locked
+func (d *Drone) stopVideoStreaming(dispatch bool) {
+	// Not streaming
+	if !d.videoOn {
 		return
 	}
 
-	// Create context
-	ctx, cancel := context.WithCancel(d.ctx)
-	if cmd.timeout > 0 {
-		ctx, cancel = context.WithTimeout(d.ctx, cmd.timeout)
+	// Update state
+	d.videoOn = false
+	d.videoIdle.Stop()
+	d.videoIdle = nil
+
+	// Dispatch
+	if dispatch {
+		d.e.Dispatch(VideoStoppedEvent, nil)
 	}
-	defer cancel()
+}
 
-	// Handle context
-	go func() {
-		// Wait for context to be done
-		<-ctx.Done()
+// StopVideo makes Tello stop streaming video
+func (d *Drone) StopVideo() error {
+	return d.StopVideoContext(context.Background())
+}
 
-		// Check error
-		if d.ctx.Err() != context.Canceled && ctx.Err() != context.DeadlineExceeded {
-			return
-		}
+// StopVideoContext makes Tello stop streaming video and stops waiting for the response when the context is done
+func (d *Drone) StopVideoContext(ctx context.Context) (err error) {
+	// Send cmd
+	if err = d.sendCmd(&cmd{
+		cmd:     "streamoff",
+		ctx:     ctx,
+		h:       defaultRespHandler,
+		timeout: d.o.timeouts.fallback(),
+	}); err != nil {
+		err = fmt.Errorf("astitello: sending streamoff cmd failed: %w", err)
+		return
+	}
 
-		// Signal
-		d.rc.L.Lock()
-		d.rc.Signal()
-		d.rc.L.Unlock()
-	}()
+	// Discard the partial packet
+	d.resetVideo()
 
-	// Wait for response
-	d.rc.Wait()
+	// Update streaming state
+	d.mvs.Lock()
+	d.stopVideoStreaming(true)
+	d.mvs.Unlock()
+	return
+}
 
-	// Check context
-	if ctx.Err() != nil {
-		err = ctx.Err()
+// SetVideoBitrate sets the video bitrate to x Mbps, 0 meaning auto
+// Valid values are between 0 and 5. This only applies while video is streaming.
+func (d *Drone) SetVideoBitrate(x int) (err error) {
+	// Validate
+	if x < 0 || x > 5 {
+		err = fmt.Errorf("astitello: bitrate %d is not between 0 and 5: %w", x, ErrInvalidArgument)
 		return
 	}
 
-	// Custom
-	if err = cmd.h(d.lr); err != nil {
-		err = fmt.Errorf("astitello: custom handler failed: %w", err)
+	// Check capabilities
+	if err = d.checkCapability("setbitrate", func(c Capabilities) bool { return c.VideoConfig }, "requires SDK 3.0"); err != nil {
+		return
+	}
+
+	// Send cmd
+	if err = d.sendCmd(&cmd{
+		cmd:     fmt.Sprintf("setbitrate %d", x),
+		h:       defaultRespHandler,
+		timeout: d.o.timeouts.fallback(),
+	}); err != nil {
+		err = fmt.Errorf("astitello: sending setbitrate cmd failed: %w", err)
 		return
 	}
 	return
 }
 
-func (d *Drone) command() (err error) {
-	// Send "command" cmd
+// SetVideoResolution sets the video resolution
+// Check out Resolution... constants for available resolutions
+func (d *Drone) SetVideoResolution(r Resolution) (err error) {
+	// Validate
+	switch r {
+	case ResolutionHigh, ResolutionLow:
+	default:
+		err = fmt.Errorf("astitello: unknown resolution %s: %w", r, ErrInvalidArgument)
+		return
+	}
+
+	// Check capabilities
+	if err = d.checkCapability("setresolution", func(c Capabilities) bool { return c.VideoConfig }, "requires SDK 3.0"); err != nil {
+		return
+	}
+
+	// Send cmd
 	if err = d.sendCmd(&cmd{
-		cmd:     "command",
+		cmd:     fmt.Sprintf("setresolution %s", r),
 		h:       defaultRespHandler,
-		timeout: defaultTimeout,
+		timeout: d.o.timeouts.fallback(),
 	}); err != nil {
-		err = fmt.Errorf("astitello: sending 'command' cmd failed: %w", err)
+		err = fmt.Errorf("astitello: sending setresolution cmd failed: %w", err)
 		return
 	}
 	return
 }
 
-// StartVideo makes Tello start streaming video
-func (d *Drone) StartVideo() (err error) {
+// SetVideoFPS sets the video frame rate
+// Check out FPS... constants for available frame rates
+func (d *Drone) SetVideoFPS(f FPS) (err error) {
+	// Validate
+	switch f {
+	case FPSHigh, FPSLow, FPSMiddle:
+	default:
+		err = fmt.Errorf("astitello: unknown fps %s: %w", f, ErrInvalidArgument)
+		return
+	}
+
+	// Check capabilities
+	if err = d.checkCapability("setfps", func(c Capabilities) bool { return c.VideoConfig }, "requires SDK 3.0"); err != nil {
+		return
+	}
+
 	// Send cmd
 	if err = d.sendCmd(&cmd{
-		cmd:     "streamon",
+		cmd:     fmt.Sprintf("setfps %s", f),
 		h:       defaultRespHandler,
-		timeout: defaultTimeout,
+		timeout: d.o.timeouts.fallback(),
 	}); err != nil {
-		err = fmt.Errorf("astitello: sending streamon cmd failed: %w", err)
+		err = fmt.Errorf("astitello: sending setfps cmd failed: %w", err)
 		return
 	}
 	return
 }
 
-// StopVideo makes Tello stop streaming video
-func (d *Drone) StopVideo() (err error) {
+// SetCameraDirection switches the video stream between the forward camera and the downward vision sensor
+// The content of the video stream changes accordingly
+func (d *Drone) SetCameraDirection(forward bool) (err error) {
+	// Get value
+	v := 1
+	if forward {
+		v = 0
+	}
+
 	// Send cmd
 	if err = d.sendCmd(&cmd{
-		cmd:     "streamoff",
+		cmd:     fmt.Sprintf("downvision %d", v),
 		h:       defaultRespHandler,
-		timeout: defaultTimeout,
+		timeout: d.o.timeouts.fallback(),
 	}); err != nil {
-		err = fmt.Errorf("astitello: sending streamoff cmd failed: %w", err)
+		err = fmt.Errorf("astitello: sending downvision cmd failed: %w", err)
 		return
 	}
 	return
@@ -537,163 +2225,294 @@ func (d *Drone) Emergency() (err error) {
 	if err = d.sendCmd(&cmd{
 		canceller: true,
 		cmd:       "emergency",
-		timeout:   defaultTimeout,
+		timeout:   d.o.timeouts.fallback(),
 	}); err != nil {
 		err = fmt.Errorf("astitello: sending emergency cmd failed: %w", err)
 		return
 	}
+
+	// Update flight state
+	d.setFlightState(FlightStateGrounded)
 	return
 }
 
 // TakeOff makes Tello auto takeoff
 func (d *Drone) TakeOff() (err error) {
+	// Check battery
+	if err = d.checkTakeOffBattery(); err != nil {
+		return
+	}
+
+	// Update flight state
+	previous := d.setFlightState(FlightStateTakingOff)
+
 	// Send cmd
 	if err = d.sendCmd(&cmd{
 		cmd:     "takeoff",
 		h:       d.respHandlerWithEvent(TakeOffEvent),
-		timeout: 20 * time.Second,
+		timeout: d.o.timeouts.takeOff(),
 	}); err != nil {
+		d.setFlightState(previous)
 		err = fmt.Errorf("astitello: sending takeoff cmd failed: %w", err)
 		return
 	}
+
+	// Update flight state
+	d.setFlightState(FlightStateAirborne)
+
+	// Positions are now relative to this take off
+	d.resetDisplacement()
+	return
+}
+
+func (d *Drone) checkTakeOffBattery() (err error) {
+	// Check is disabled
+	if d.o.minTakeoffBattery <= 0 {
+		return
+	}
+
+	// Get battery from the latest state
+	d.ms.Lock()
+	ok := !d.stateAt.IsZero()
+	b := d.s.Battery
+	d.ms.Unlock()
+
+	// No state has been received yet
+	if !ok {
+		if b, err = d.Battery(); err != nil {
+			err = fmt.Errorf("astitello: getting battery failed: %w", err)
+			return
+		}
+	}
+
+	// Check battery
+	if b < d.o.minTakeoffBattery {
+		err = fmt.Errorf("astitello: battery %d%% is below %d%%: %w", b, d.o.minTakeoffBattery, ErrBatteryTooLow)
+		return
+	}
 	return
 }
 
 // Land makes Tello auto land
+// It is a no-op when the drone is known to be on the ground, which makes it safe to defer.
 func (d *Drone) Land() (err error) {
+	// Update flight state
+	previous, ok := d.startLanding()
+	if !ok {
+		return
+	}
+
 	// Send cmd
 	if err = d.sendCmd(&cmd{
 		canceller: true,
 		cmd:       "land",
 		h:         d.respHandlerWithEvent(LandEvent),
-		timeout:   20 * time.Second,
+		timeout:   d.o.timeouts.land(),
 	}); err != nil {
+		d.setFlightState(previous)
 		err = fmt.Errorf("astitello: sending land cmd failed: %w", err)
 		return
 	}
+
+	// Update flight state
+	d.setFlightState(FlightStateGrounded)
 	return
 }
 
 // Up makes Tello fly up with distance x cm
 func (d *Drone) Up(x int) (err error) {
+	// Check flight state
+	if err = d.checkAirborne(); err != nil {
+		return
+	}
+
 	// Send cmd
 	if err = d.sendCmd(&cmd{
 		cmd:     fmt.Sprintf("up %d", x),
 		h:       defaultRespHandler,
-		timeout: time.Minute,
+		timeout: d.o.timeouts.move(),
 	}); err != nil {
 		err = fmt.Errorf("astitello: sending up cmd failed: %w", err)
 		return
 	}
+
+	// Update displacement
+	d.addMove(0, 0, x)
 	return
 }
 
 // Down makes Tello fly down with distance x cm
 func (d *Drone) Down(x int) (err error) {
+	// Check flight state
+	if err = d.checkAirborne(); err != nil {
+		return
+	}
+
 	// Send cmd
 	if err = d.sendCmd(&cmd{
 		cmd:     fmt.Sprintf("down %d", x),
 		h:       defaultRespHandler,
-		timeout: time.Minute,
+		timeout: d.o.timeouts.move(),
 	}); err != nil {
 		err = fmt.Errorf("astitello: sending down cmd failed: %w", err)
 		return
 	}
+
+	// Update displacement
+	d.addMove(0, 0, -x)
 	return
 }
 
 // Left makes Tello fly left with distance x cm
 func (d *Drone) Left(x int) (err error) {
+	// Check flight state
+	if err = d.checkAirborne(); err != nil {
+		return
+	}
+
 	// Send cmd
 	if err = d.sendCmd(&cmd{
 		cmd:     fmt.Sprintf("left %d", x),
 		h:       defaultRespHandler,
-		timeout: time.Minute,
+		timeout: d.o.timeouts.move(),
 	}); err != nil {
 		err = fmt.Errorf("astitello: sending left cmd failed: %w", err)
 		return
 	}
+
+	// Update displacement
+	d.addMove(0, x, 0)
 	return
 }
 
 // Right makes Tello fly right with distance x cm
 func (d *Drone) Right(x int) (err error) {
+	// Check flight state
+	if err = d.checkAirborne(); err != nil {
+		return
+	}
+
 	// Send cmd
 	if err = d.sendCmd(&cmd{
 		cmd:     fmt.Sprintf("right %d", x),
 		h:       defaultRespHandler,
-		timeout: time.Minute,
+		timeout: d.o.timeouts.move(),
 	}); err != nil {
 		err = fmt.Errorf("astitello: sending right cmd failed: %w", err)
 		return
 	}
+
+	// Update displacement
+	d.addMove(0, -x, 0)
 	return
 }
 
 // Forward makes Tello fly forward with distance x cm
 func (d *Drone) Forward(x int) (err error) {
+	// Check flight state
+	if err = d.checkAirborne(); err != nil {
+		return
+	}
+
 	// Send cmd
 	if err = d.sendCmd(&cmd{
 		cmd:     fmt.Sprintf("forward %d", x),
 		h:       defaultRespHandler,
-		timeout: time.Minute,
+		timeout: d.o.timeouts.move(),
 	}); err != nil {
 		err = fmt.Errorf("astitello: sending forward cmd failed: %w", err)
 		return
 	}
+
+	// Update displacement
+	d.addMove(x, 0, 0)
 	return
 }
 
 // Back makes Tello fly back with distance x cm
 func (d *Drone) Back(x int) (err error) {
+	// Check flight state
+	if err = d.checkAirborne(); err != nil {
+		return
+	}
+
 	// Send cmd
 	if err = d.sendCmd(&cmd{
 		cmd:     fmt.Sprintf("back %d", x),
 		h:       defaultRespHandler,
-		timeout: time.Minute,
+		timeout: d.o.timeouts.move(),
 	}); err != nil {
 		err = fmt.Errorf("astitello: sending back cmd failed: %w", err)
 		return
 	}
+
+	// Update displacement
+	d.addMove(-x, 0, 0)
 	return
 }
 
 // RotateClockwise makes Tello rotate x degree clockwise
 func (d *Drone) RotateClockwise(x int) (err error) {
+	// Check flight state
+	if err = d.checkAirborne(); err != nil {
+		return
+	}
+
 	// Send cmd
 	if err = d.sendCmd(&cmd{
 		cmd:     fmt.Sprintf("cw %d", x),
 		h:       defaultRespHandler,
-		timeout: time.Minute,
+		timeout: d.o.timeouts.move(),
 	}); err != nil {
 		err = fmt.Errorf("astitello: sending cw cmd failed: %w", err)
 		return
 	}
+
+	// Update displacement
+	d.addRotation(x)
 	return
 }
 
 // RotateCounterClockwise makes Tello rotate x degree counter-clockwise
 func (d *Drone) RotateCounterClockwise(x int) (err error) {
+	// Check flight state
+	if err = d.checkAirborne(); err != nil {
+		return
+	}
+
 	// Send cmd
 	if err = d.sendCmd(&cmd{
 		cmd:     fmt.Sprintf("ccw %d", x),
 		h:       defaultRespHandler,
-		timeout: time.Minute,
+		timeout: d.o.timeouts.move(),
 	}); err != nil {
 		err = fmt.Errorf("astitello: sending ccw cmd failed: %w", err)
 		return
 	}
+
+	// Update displacement
+	d.addRotation(-x)
 	return
 }
 
 // Flip makes Tello flip in the specified direction
 // Check out Flip... constants for available flip directions
-func (d *Drone) Flip(x string) (err error) {
+func (d *Drone) Flip(x FlipDirection) (err error) {
+	// Validate
+	if err = validateFlipDirection(x); err != nil {
+		return
+	}
+
+	// Check flight state
+	if err = d.checkAirborne(); err != nil {
+		return
+	}
+
 	// Send cmd
 	if err = d.sendCmd(&cmd{
 		cmd:     fmt.Sprintf("flip %s", x),
 		h:       defaultRespHandler,
-		timeout: 20 * time.Second,
+		timeout: d.o.timeouts.flip(),
 	}); err != nil {
 		err = fmt.Errorf("astitello: sending flip cmd failed: %w", err)
 		return
@@ -702,30 +2521,70 @@ func (d *Drone) Flip(x string) (err error) {
 }
 
 // Go makes Tello fly to x y z in speed (cm/s)
+// Coordinates must be between -500 and 500, but not all between -20 and 20, and speed between 10 and 100
 func (d *Drone) Go(x, y, z, speed int) (err error) {
+	// Validate
+	if err = validateGo(x, y, z, speed); err != nil {
+		return
+	}
+
+	// Check flight state
+	if err = d.checkAirborne(); err != nil {
+		return
+	}
+
 	// Send cmd
 	if err = d.sendCmd(&cmd{
 		cmd:     fmt.Sprintf("go %d %d %d %d", x, y, z, speed),
 		h:       defaultRespHandler,
-		timeout: time.Minute,
+		timeout: d.o.timeouts.move(),
 	}); err != nil {
 		err = fmt.Errorf("astitello: sending go cmd failed: %w", err)
 		return
 	}
+
+	// Update displacement
+	d.addMove(x, y, z)
 	return
 }
 
+// Move makes Tello fly to the point located dx cm forward, dy cm left and dz cm up from its current position
+// with speed (cm/s). Use negative values to fly backward, right or down. Axes are relative to the drone's
+// heading, not to its take off position.
+// It is validated the same way as Go, which it is an alias of unless MovementModeRC is used, in which case
+// sticks positions are set instead for as long as the movement should take.
+func (d *Drone) Move(dx, dy, dz, speed int) error {
+	if d.o.movementMode == MovementModeRC {
+		return d.moveRC(dx, dy, dz, speed)
+	}
+	return d.Go(dx, dy, dz, speed)
+}
+
 // Curve makes Tello fly a curve defined by the current and two given coordinates with speed (cm/s)
+// Coordinates are validated with ValidateCurve and speed must be between 10 and 60.
 func (d *Drone) Curve(x1, y1, z1, x2, y2, z2, speed int) (err error) {
+	// Validate
+	if err = validateCurve(x1, y1, z1, x2, y2, z2, speed); err != nil {
+		return
+	}
+
+	// Check flight state
+	if err = d.checkAirborne(); err != nil {
+		return
+	}
+
 	// Send cmd
 	if err = d.sendCmd(&cmd{
 		cmd:     fmt.Sprintf("curve %d %d %d %d %d %d %d", x1, y1, z1, x2, y2, z2, speed),
 		h:       defaultRespHandler,
-		timeout: time.Minute,
+		timeout: d.o.timeouts.move(),
 	}); err != nil {
-		err = fmt.Errorf("astitello: sending go cmd failed: %w", err)
+		err = fmt.Errorf("astitello: sending curve cmd failed: %w", err)
 		return
 	}
+
+	// Update displacement
+	d.addMove(x2, y2, z2)
 	return
 }
 
@@ -736,11 +2595,30 @@ func (d *Drone) Curve(x1, y1, z1, x2, y2, z2, speed int) (err error) {
 // ud: up/down
 // y: yawn
 // This cmd doesn't seem to be receiving any response, that's why we don't provide any handler
+// When WithRCMaxRate is used, positions are sent asynchronously and only the latest ones are kept.
 func (d *Drone) SetSticks(lr, fb, ud, y int) (err error) {
+	// Validate
+	if err = validateSticks(lr, fb, ud, y); err != nil {
+		return
+	}
+
+	// Coalesce
+	if d.o.rcMaxRate > 0 {
+		if !d.Connected() {
+			err = ErrNotConnected
+			return
+		}
+		d.coalesceSticks([4]int{lr, fb, ud, y})
+		return
+	}
+	return d.sendSticks(lr, fb, ud, y)
+}
+
+func (d *Drone) sendSticks(lr, fb, ud, y int) (err error) {
 	// Send cmd
 	if err = d.sendCmd(&cmd{
 		cmd:     fmt.Sprintf("rc %d %d %d %d", lr, fb, ud, y),
-		timeout: defaultTimeout,
+		timeout: d.o.timeouts.fallback(),
 	}); err != nil {
 		err = fmt.Errorf("astitello: sending rc cmd failed: %w", err)
 		return
@@ -748,38 +2626,227 @@ func (d *Drone) SetSticks(lr, fb, ud, y int) (err error) {
 	return
 }
 
+// Hover sets all sticks to their neutral position, which is what should be sent when releasing a joystick
+func (d *Drone) Hover() error {
+	return d.SetSticks(0, 0, 0, 0)
+}
+
+func validateCurve(x1, y1, z1, x2, y2, z2, speed int) (err error) {
+	// Validate points
+	if err = ValidateCurve(x1, y1, z1, x2, y2, z2); err != nil {
+		return
+	}
+
+	// Validate speed
+	if speed < 10 || speed > 60 {
+		err = fmt.Errorf("astitello: speed %d is not between 10 and 60: %w", speed, ErrInvalidArgument)
+		return
+	}
+	return
+}
+
+// ValidateCurve checks that the drone accepts a curve defined by the current and two given coordinates (cm)
+// before sending it: coordinates must be between -500 and 500, and the radius of the arc going through the
+// three points must be between 0.5 and 10m. The drone rejects invalid curves with an opaque error.
+// Curve() already calls it.
+func ValidateCurve(x1, y1, z1, x2, y2, z2 int) (err error) {
+	// Validate coordinates
+	for _, v := range []struct {
+		name string
+		x    int
+	}{
+		{name: "x1", x: x1},
+		{name: "y1", x: y1},
+		{name: "z1", x: z1},
+		{name: "x2", x: x2},
+		{name: "y2", x: y2},
+		{name: "z2", x: z2},
+	} {
+		if v.x < -500 || v.x > 500 {
+			err = fmt.Errorf("astitello: %s %d is not between -500 and 500: %w", v.name, v.x, ErrInvalidArgument)
+			return
+		}
+	}
+
+	// Validate radius
+	r, ok := curveRadius(float64(x1), float64(y1), float64(z1), float64(x2), float64(y2), float64(z2))
+	if !ok {
+		err = fmt.Errorf("astitello: curve points are aligned: %w", ErrInvalidArgument)
+		return
+	}
+	if r < 50 || r > 1000 {
+		err = fmt.Errorf("astitello: curve radius %.0fcm is not between 50 and 1000: %w", r, ErrInvalidArgument)
+		return
+	}
+	return
+}
+
+// curveRadius returns the radius of the circle going through the origin and the two given points, which is the
+// product of the triangle's sides divided by 4 times its area. It returns false if the points are aligned.
+func curveRadius(x1, y1, z1, x2, y2, z2 float64) (r float64, ok bool) {
+	// Get cross product, whose norm is twice the triangle's area
+	cx, cy, cz := y1*z2-z1*y2, z1*x2-x1*z2, x1*y2-y1*x2
+	area2 := math.Sqrt(cx*cx + cy*cy + cz*cz)
+	if area2 == 0 {
+		return
+	}
+
+	// Get sides
+	a := math.Sqrt(x1*x1 + y1*y1 + z1*z1)
+	b := math.Sqrt(x2*x2 + y2*y2 + z2*z2)
+	c := math.Sqrt((x2-x1)*(x2-x1) + (y2-y1)*(y2-y1) + (z2-z1)*(z2-z1))
+	return a * b * c / (2 * area2), true
+}
+
+func validateGo(x, y, z, speed int) (err error) {
+	// Validate coordinates
+	for _, v := range []struct {
+		name string
+		x    int
+	}{
+		{name: "x", x: x},
+		{name: "y", x: y},
+		{name: "z", x: z},
+	} {
+		if v.x < -500 || v.x > 500 {
+			err = fmt.Errorf("astitello: %s %d is not between -500 and 500: %w", v.name, v.x, ErrInvalidArgument)
+			return
+		}
+	}
+
+	// The drone rejects destinations too close to its position
+	if abs(x) <= 20 && abs(y) <= 20 && abs(z) <= 20 {
+		err = fmt.Errorf("astitello: x %d, y %d and z %d are all between -20 and 20: %w", x, y, z, ErrInvalidArgument)
+		return
+	}
+
+	// Validate speed
+	if speed < 10 || speed > 100 {
+		err = fmt.Errorf("astitello: speed %d is not between 10 and 100: %w", speed, ErrInvalidArgument)
+		return
+	}
+	return
+}
+
+func validateSticks(lr, fb, ud, y int) (err error) {
+	for _, v := range []struct {
+		name string
+		x    int
+	}{
+		{name: "lr", x: lr},
+		{name: "fb", x: fb},
+		{name: "ud", x: ud},
+		{name: "y", x: y},
+	} {
+		if v.x < -100 || v.x > 100 {
+			err = fmt.Errorf("astitello: %s stick %d is not between -100 and 100: %w", v.name, v.x, ErrInvalidArgument)
+			return
+		}
+	}
+	return
+}
+
 // SetWifi sets Wi-Fi with SSID password
-// I couldn't make this work (it returned 'error' even though the SSID was changed but the password was not)
-// If anyone manages to make it work, create an issue in github, I'm really interested in how you managed that :D
+// Once the drone has rebooted with its new access point, the connection is lost: join the new network and use
+// Reconnect(), or WithAutoReconnect, to control the drone again. The WifiChanged event is dispatched with the new
+// SSID once it has been changed.
+// The drone is known to respond with an error even though the SSID has changed but the password has not. When
+// the drone can report its SSID, which requires SDK 3.0 and capabilities to have been probed, it is read back
+// and a WifiError indicating which part failed is returned.
 func (d *Drone) SetWifi(ssid, password string) (err error) {
 	// Send cmd
 	if err = d.sendCmd(&cmd{
 		cmd:     fmt.Sprintf("wifi %s %s", ssid, password),
 		h:       defaultRespHandler,
-		timeout: defaultTimeout,
+		timeout: d.o.timeouts.fallback(),
 	}); err != nil {
-		err = fmt.Errorf("astitello: sending wifi cmd failed: %w", err)
+		// Only error responses may have been partly applied
+		var de *DroneError
+		if !errors.As(err, &de) {
+			err = fmt.Errorf("astitello: sending wifi cmd failed: %w", err)
+			return
+		}
+
+		// Read SSID back
+		we := &WifiError{Err: err}
+		if c := d.Capabilities(); c.Probed && c.VideoConfig {
+			if s, qerr := d.ssid(); qerr != nil {
+				d.l.Error(fmt.Errorf("astitello: reading ssid back failed: %w", qerr))
+			} else if s == ssid {
+				we.Part = WifiPartPassword
+			} else {
+				we.Part = WifiPartSSID
+			}
+		}
+
+		// Dispatch
+		if we.Part == WifiPartPassword {
+			d.e.Dispatch(WifiChangedEvent, ssid)
+		}
+		err = we
 		return
 	}
+
+	// Dispatch
+	d.e.Dispatch(WifiChangedEvent, ssid)
 	return
 }
 
-// Wifi returns the Wifi SNR
-func (d *Drone) Wifi() (snr int, err error) {
+// ssid returns the SSID of the drone's access point, which requires SDK 3.0
+func (d *Drone) ssid() (ssid string, err error) {
+	err = d.query("ssid?", func(resp string) (err error) {
+		ssid, err = parseString(resp)
+		return
+	})
+	return
+}
+
+// ConnectToAP makes Tello EDU reboot and join an existing Wi-Fi network as a client instead of hosting its
+// own access point. Once it has joined the network, it is not reachable at 192.168.10.1 anymore: close the
+// drone and create a new one using the WithCommandAddr option with the address your router assigned to it.
+func (d *Drone) ConnectToAP(ssid, password string) (err error) {
+	// Check capabilities
+	if err = d.checkCapability("ap", func(c Capabilities) bool { return c.AP }, "requires SDK 2.0"); err != nil {
+		return
+	}
+
 	// Send cmd
-	// It returns "100.0"
 	if err = d.sendCmd(&cmd{
-		cmd: "wifi?",
-		h: func(resp string) (err error) {
-			// Parse
-			if snr, err = strconv.Atoi(resp); err != nil {
-				err = fmt.Errorf("astitello: atoi %s failed: %w", resp, err)
-				return
-			}
-			return
-		},
-		timeout: defaultTimeout,
+		cmd:     fmt.Sprintf("ap %s %s", ssid, password),
+		h:       defaultRespHandler,
+		timeout: d.o.timeouts.fallback(),
 	}); err != nil {
+		err = fmt.Errorf("astitello: sending ap cmd failed: %w", err)
+		return
+	}
+	return
+}
+
+// Wifi returns the Wifi SNR
+func (d *Drone) Wifi() (snr int, err error) {
+	// It returns "100.0"
+	err = d.query("wifi?", func(resp string) (err error) {
+		snr, err = parseFloatAsInt(resp)
+		return
+	})
+	return
+}
+
+// Ping sends the wifi? cmd, which is cheap and has no side effect, and returns the round-trip time between
+// writing it and receiving its response. Time spent waiting for previous cmds to be done is not included.
+// If no response is received before the query timeout, the error wraps ErrTimeout.
+func (d *Drone) Ping(ctx context.Context) (rtt time.Duration, err error) {
+	// Send cmd
+	c := &cmd{
+		cmd:     "wifi?",
+		ctx:     ctx,
+		timeout: d.o.timeouts.query(),
+	}
+	c.h = func(resp string) (err error) {
+		rtt = time.Since(c.sentAt)
+		return
+	}
+	if err = d.sendCmd(c); err != nil {
 		err = fmt.Errorf("astitello: sending wifi? cmd failed: %w", err)
 		return
 	}
@@ -792,7 +2859,7 @@ func (d *Drone) SetSpeed(x int) (err error) {
 	if err = d.sendCmd(&cmd{
 		cmd:     fmt.Sprintf("speed %d", x),
 		h:       defaultRespHandler,
-		timeout: defaultTimeout,
+		timeout: d.o.timeouts.fallback(),
 	}); err != nil {
 		err = fmt.Errorf("astitello: sending speed cmd failed: %w", err)
 		return
@@ -800,28 +2867,35 @@ func (d *Drone) SetSpeed(x int) (err error) {
 	return
 }
 
+// SetSpeedVerified sets the speed (cm/s) and queries it back to make sure the drone applied it
+// If the drone reports a different speed, a SpeedMismatchError containing the applied speed is returned.
+func (d *Drone) SetSpeedVerified(x int) (err error) {
+	// Set speed
+	if err = d.SetSpeed(x); err != nil {
+		return
+	}
+
+	// Get speed
+	var a int
+	if a, err = d.Speed(); err != nil {
+		err = fmt.Errorf("astitello: getting speed failed: %w", err)
+		return
+	}
+
+	// Speed has not been applied
+	if a != x {
+		err = &SpeedMismatchError{Applied: a, Requested: x}
+		return
+	}
+	return
+}
+
 // Speed returns the current speed (cm/s)
 func (d *Drone) Speed() (x int, err error) {
-	// Send cmd
 	// It returns "100.0"
-	if err = d.sendCmd(&cmd{
-		cmd: "speed?",
-		h: func(resp string) (err error) {
-			// Parse
-			var f float64
-			if f, err = strconv.ParseFloat(resp, 64); err != nil {
-				err = fmt.Errorf("astitello: parsing float %s failed: %w", resp, err)
-				return
-			}
-
-			// Set speed
-			x = int(f)
-			return
-		},
-		timeout: defaultTimeout,
-	}); err != nil {
-		err = fmt.Errorf("astitello: sending speed? cmd failed: %w", err)
+	err = d.query("speed?", func(resp string) (err error) {
+		x, err = parseFloatAsInt(resp)
 		return
-	}
+	})
 	return
 }
@@ -5,11 +5,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"net"
 	"reflect"
+	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/asticode/go-astikit"
 )
 
 var (
@@ -24,15 +28,17 @@ type dialer struct {
 	h       func([]byte) []byte
 	laddr   string
 	raddr   string
+	mr      *sync.Mutex // Locks rs
 	mt      *sync.Mutex // Locks timeout
 	rs      []string
-	t       *testing.T
+	t       testing.TB
 	timeout bool
 }
 
-func newDialer(t *testing.T, laddr, raddr string) *dialer {
+func newDialer(t testing.TB, laddr, raddr string) *dialer {
 	return &dialer{
 		laddr: laddr,
+		mr:    &sync.Mutex{},
 		mt:    &sync.Mutex{},
 		raddr: raddr,
 		t:     t,
@@ -82,7 +88,9 @@ func (d *dialer) start() (err error) {
 			}
 
 			// Append
+			d.mr.Lock()
 			d.rs = append(d.rs, string(b[:n]))
+			d.mr.Unlock()
 
 			// Handle
 			d.mt.Lock()
@@ -101,6 +109,12 @@ func (d *dialer) start() (err error) {
 	return
 }
 
+func (d *dialer) received() []string {
+	d.mr.Lock()
+	defer d.mr.Unlock()
+	return append([]string{}, d.rs...)
+}
+
 func (d *dialer) close() {
 	if d.cancel != nil {
 		d.cancel()
@@ -110,7 +124,7 @@ func (d *dialer) close() {
 	}
 }
 
-func setup(t *testing.T) (d *Drone, c, s, v *dialer, err error) {
+func setup(t testing.TB, opts ...Option) (d *Drone, c, s, v *dialer, err error) {
 	// Create cmd dialer
 	c = newDialer(t, "127.0.0.1:", respAddr)
 
@@ -122,12 +136,32 @@ func setup(t *testing.T) (d *Drone, c, s, v *dialer, err error) {
 		// Switch on command
 		switch string(cmd) {
 		case "command", "takeoff", "land", "up 1", "down 1", "left 1", "right 1", "forward 1", "back 1", "cw 1",
-			"ccw 1", "flip l", "go 1 2 3 4", "curve 1 2 3 4 5 6 7", "wifi 1 2", "speed 1", "streamon", "streamoff":
+			"ccw 1", "flip l", "go 100 2 3 10", "curve 100 100 0 200 0 0 10", "wifi 1 2", "speed 1", "streamon", "streamoff", "setbitrate 1", "setresolution high", "setfps low", "downvision 0", "downvision 1", "ap ssid password":
 			resp = []byte("ok")
 		case "speed?":
 			resp = []byte("100.0")
 		case "wifi?":
 			resp = []byte("100")
+		case "battery?":
+			resp = []byte("50")
+		case "height?":
+			resp = []byte("10dm")
+		case "tof?":
+			resp = []byte("105mm")
+		case "baro?":
+			resp = []byte("-64.105316")
+		case "temp?":
+			resp = []byte("14~15C")
+		case "attitude?":
+			resp = []byte("pitch:1;roll:-2;yaw:3;")
+		case "acceleration?":
+			resp = []byte("agx:-3.00;agy:0.00;agz:-998.00;")
+		case "EXT tof?":
+			resp = []byte("tof 1234")
+		case "sdk?":
+			resp = []byte("20")
+		case "sn?":
+			resp = []byte("0TQDG2KEDB4F7X")
 		}
 		return
 	}
@@ -160,7 +194,30 @@ func setup(t *testing.T) (d *Drone, c, s, v *dialer, err error) {
 	cmdAddr = c.conn.LocalAddr().String()
 
 	// Create drone
-	d = New(nil)
+	d = New(nil, opts...)
+	return
+}
+
+func setupAndStart(t testing.TB, opts ...Option) (d *Drone, c, s, v *dialer, teardown func()) {
+	// Set up
+	d, c, s, v, err := setup(t, opts...)
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: setting up failed: %w", err))
+	}
+
+	// Create teardown
+	teardown = func() {
+		d.Close()
+		c.close()
+		s.close()
+		v.close()
+	}
+
+	// Start
+	if err = d.Start(); err != nil {
+		teardown()
+		t.Fatal(fmt.Errorf("test: starting the drone failed: %w", err))
+	}
 	return
 }
 
@@ -183,6 +240,9 @@ func TestDrone(t *testing.T) {
 	}
 	defer d.Close()
 
+	// Capabilities are unknown, therefore all cmds are attempted
+	d.resetCapabilities()
+
 	// Handle events
 	me := &sync.Mutex{} // Locks events
 	landed := false
@@ -193,7 +253,6 @@ func TestDrone(t *testing.T) {
 	for idx, f := range []func() error{
 		d.Emergency,
 		d.TakeOff,
-		d.Land,
 		func() error { return d.Up(1) },
 		func() error { return d.Down(1) },
 		func() error { return d.Left(1) },
@@ -203,19 +262,80 @@ func TestDrone(t *testing.T) {
 		func() error { return d.RotateClockwise(1) },
 		func() error { return d.RotateCounterClockwise(1) },
 		func() error { return d.Flip(FlipLeft) },
-		func() error { return d.Go(1, 2, 3, 4) },
-		func() error { return d.Curve(1, 2, 3, 4, 5, 6, 7) },
+		func() error { return d.Go(100, 2, 3, 10) },
+		func() error { return d.Curve(100, 100, 0, 200, 0, 0, 10) },
+		d.Land,
 		func() error { return d.SetSticks(1, 2, 3, 4) },
+		d.Hover,
 		func() error { return d.SetWifi("1", "2") },
 		func() error { return d.SetSpeed(1) },
 		func() error { return d.StartVideo() },
 		func() error { return d.StopVideo() },
+		func() error { return d.SetVideoBitrate(1) },
+		func() error { return d.SetVideoResolution(ResolutionHigh) },
+		func() error { return d.SetVideoFPS(FPSLow) },
+		func() error { return d.SetCameraDirection(true) },
+		func() error { return d.SetCameraDirection(false) },
+		func() error { return d.ConnectToAP("ssid", "password") },
 	} {
 		if err = f(); err != nil {
 			t.Error(fmt.Errorf("err %d should be nil", idx))
 		}
 	}
 
+	// Invalid sticks
+	for _, f := range []func() error{
+		func() error { return d.SetSticks(101, 0, 0, 0) },
+		func() error { return d.SetSticks(0, -101, 0, 0) },
+		func() error { return d.SetSticks(0, 0, 200, 0) },
+		func() error { return d.SetSticks(0, 0, 0, -150) },
+	} {
+		if err = f(); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("expected %s, got %s", ErrInvalidArgument, err)
+		}
+	}
+
+	// Invalid go
+	for _, f := range []func() error{
+		func() error { return d.Go(501, 0, 0, 10) },
+		func() error { return d.Go(0, -501, 0, 10) },
+		func() error { return d.Go(0, 0, 0, 10) },
+		func() error { return d.Go(20, -20, 20, 10) },
+		func() error { return d.Go(100, 0, 0, 9) },
+		func() error { return d.Go(100, 0, 0, 101) },
+	} {
+		if err = f(); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("expected %s, got %s", ErrInvalidArgument, err)
+		}
+	}
+
+	// Valid go boundaries
+	for _, v := range [][]int{{-500, -500, -500, 10}, {500, 500, 500, 100}, {21, 0, 0, 10}} {
+		if err = validateGo(v[0], v[1], v[2], v[3]); err != nil {
+			t.Errorf("expected nil, got %s", err)
+		}
+	}
+
+	// Invalid curves
+	for _, f := range []func() error{
+		func() error { return d.Curve(-501, 0, 0, 100, 100, 0, 10) },
+		func() error { return d.Curve(100, 100, 0, 0, 0, 501, 10) },
+		func() error { return d.Curve(100, 100, 0, 200, 0, 0, 9) },
+		func() error { return d.Curve(100, 100, 0, 200, 0, 0, 61) },
+		func() error { return d.Curve(1, 2, 3, 4, 5, 6, 10) },
+	} {
+		if err = f(); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("expected %s, got %s", ErrInvalidArgument, err)
+		}
+	}
+
+	// Valid curve boundaries
+	for _, v := range [][]int{{-500, -500, 0, 0, -500, 0, 10}, {100, 100, 0, 200, 0, 0, 60}} {
+		if err = validateCurve(v[0], v[1], v[2], v[3], v[4], v[5], v[6]); err != nil {
+			t.Errorf("expected nil, got %s", err)
+		}
+	}
+
 	// Wifi
 	var snr int
 	if snr, err = d.Wifi(); err != nil {
@@ -233,23 +353,37 @@ func TestDrone(t *testing.T) {
 	}
 
 	// Cmds
-	e := []string{"command", "emergency", "takeoff", "land", "up 1", "down 1", "left 1", "right 1", "forward 1",
-		"back 1", "cw 1", "ccw 1", "flip l", "go 1 2 3 4", "curve 1 2 3 4 5 6 7", "rc 1 2 3 4", "wifi 1 2", "speed 1",
-		"streamon", "streamoff", "wifi?", "speed?"}
-	if !reflect.DeepEqual(c.rs, e) {
-		t.Errorf("expected cmds %+v, got %+v", e, c.rs)
+	e := []string{"command", "sdk?", "sn?", "emergency", "takeoff", "up 1", "down 1", "left 1", "right 1", "forward 1",
+		"back 1", "cw 1", "ccw 1", "flip l", "go 100 2 3 10", "curve 100 100 0 200 0 0 10", "land", "rc 1 2 3 4", "rc 0 0 0 0", "wifi 1 2", "speed 1",
+		"streamon", "streamoff", "setbitrate 1", "setresolution high", "setfps low", "downvision 0", "downvision 1", "ap ssid password", "wifi?", "speed?"}
+	if g := c.received(); !reflect.DeepEqual(g, e) {
+		t.Errorf("expected cmds %+v, got %+v", e, g)
 	}
 
 	// Test events
 	testEvents(t, &tookOff, &landed, wg, s, v, me)
 
+	// Invalid arguments
+	for idx, f := range []func() error{
+		func() error { return d.SetVideoBitrate(6) },
+		func() error { return d.SetVideoResolution("medium") },
+		func() error { return d.SetVideoFPS("ultra") },
+		func() error { return d.Flip("x") },
+	} {
+		if err = f(); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("err %d should be %s", idx, ErrInvalidArgument)
+		}
+	}
+
 	// Timeout
+	dt := defaultTimeout
 	defaultTimeout = time.Millisecond
+	defer func() { defaultTimeout = dt }()
 	c.mt.Lock()
 	c.timeout = true
 	c.mt.Unlock()
-	if err = d.command(); err == nil || !errors.Is(err, context.DeadlineExceeded) {
-		t.Errorf("error should be %s", context.DeadlineExceeded)
+	if err = d.command(time.Time{}); err == nil || !errors.Is(err, ErrTimeout) {
+		t.Errorf("error should be %s", ErrTimeout)
 	}
 	c.mt.Lock()
 	c.timeout = false
@@ -270,6 +404,8 @@ func handleEvents(t *testing.T, d *Drone, tookOff, landed *bool, m *sync.Mutex)
 			t.Errorf("expected state %+v, got %+v", expectedState, s)
 		} else if d.State() != s {
 			t.Error("state has not been updated")
+		} else if d.RawState() != strState {
+			t.Errorf("expected raw state %s, got %s", strState, d.RawState())
 		}
 	}))
 
@@ -331,3 +467,1943 @@ func testEvents(t *testing.T, tookOff, landed *bool, wg *sync.WaitGroup, s, v *d
 		t.Error("expected landed == true, got false")
 	}
 }
+
+func TestVideoPacketReassembly(t *testing.T) {
+	// Set up and start
+	d, _, _, v, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Handle video packets
+	mp := &sync.Mutex{} // Locks ps
+	var ps [][]byte
+	d.On(VideoPacketEvent, VideoPacketEventHandler(func(p []byte) {
+		mp.Lock()
+		defer mp.Unlock()
+		ps = append(ps, p)
+	}))
+
+	// Write a packet whose length is a multiple of the MTU
+	mtu := d.o.videoMTU
+	e := bytes.Repeat([]byte("a"), 2*mtu)
+	for i := 0; i < len(e); i += mtu {
+		if _, err := v.conn.Write(e[i : i+mtu]); err != nil {
+			t.Error(fmt.Errorf("test: writing video packet failed: %w", err))
+		}
+	}
+
+	// Wait for the packet to be flushed
+	time.Sleep(4 * videoFlushTimeout)
+
+	// Check
+	mp.Lock()
+	defer mp.Unlock()
+	if len(ps) != 1 {
+		t.Errorf("expected 1 packet, got %d", len(ps))
+	} else if !bytes.Equal(ps[0], e) {
+		t.Errorf("expected packet of length %d, got length %d", len(e), len(ps[0]))
+	}
+}
+
+func TestVideoMTU(t *testing.T) {
+	// Update defaults
+	ft := videoFlushTimeout
+	videoFlushTimeout = time.Minute
+	defer func() { videoFlushTimeout = ft }()
+
+	// Set up and start
+	d, _, _, v, teardown := setupAndStart(t, WithVideoMTU(1400))
+	defer teardown()
+
+	// Handle video packets
+	ps := make(chan []byte, 10)
+	d.On(VideoPacketEvent, VideoPacketEventHandler(func(p []byte) { ps <- p }))
+
+	// Write a packet split in 1400 bytes datagrams, followed by a shorter one
+	e := append(append([]byte{}, videoStartCode...), bytes.Repeat([]byte("a"), 2*1400-len(videoStartCode)+10)...)
+	for _, b := range [][]byte{e[:1400], e[1400:2800], e[2800:]} {
+		if _, err := v.conn.Write(b); err != nil {
+			t.Fatal(fmt.Errorf("test: writing video datagram failed: %w", err))
+		}
+	}
+
+	// Check
+	select {
+	case p := <-ps:
+		if !bytes.Equal(p, e) {
+			t.Errorf("expected packet of length %d, got length %d", len(e), len(p))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected video packet")
+	}
+}
+
+func TestVideoResetOnRestart(t *testing.T) {
+	// Update defaults
+	ft := videoFlushTimeout
+	videoFlushTimeout = time.Minute
+	defer func() { videoFlushTimeout = ft }()
+
+	// Set up and start
+	d, _, _, v, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Handle video packets
+	mp := &sync.Mutex{} // Locks ps
+	var ps [][]byte
+	d.On(VideoPacketEvent, VideoPacketEventHandler(func(p []byte) {
+		mp.Lock()
+		defer mp.Unlock()
+		ps = append(ps, p)
+	}))
+
+	// Start video and write a partial packet
+	if err := d.StartVideo(); err != nil {
+		t.Fatal(fmt.Errorf("test: starting video failed: %w", err))
+	}
+	if _, err := v.conn.Write(bytes.Repeat([]byte("a"), d.o.videoMTU)); err != nil {
+		t.Error(fmt.Errorf("test: writing video packet failed: %w", err))
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	// Restart video and write a complete packet
+	if err := d.StopVideo(); err != nil {
+		t.Fatal(fmt.Errorf("test: stopping video failed: %w", err))
+	}
+	if err := d.StartVideo(); err != nil {
+		t.Fatal(fmt.Errorf("test: starting video failed: %w", err))
+	}
+	if _, err := v.conn.Write([]byte("packet")); err != nil {
+		t.Error(fmt.Errorf("test: writing video packet failed: %w", err))
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	// Check
+	mp.Lock()
+	defer mp.Unlock()
+	if len(ps) != 1 {
+		t.Errorf("expected 1 packet, got %d", len(ps))
+	} else if e, g := "packet", string(ps[0]); e != g {
+		t.Errorf("expected %s, got packet of length %d", e, len(g))
+	}
+}
+
+func TestVideoStartTimeout(t *testing.T) {
+	// Set up and start
+	d, c, _, v, teardown := setupAndStart(t, WithVideoStartTimeout(20*time.Millisecond))
+	defer teardown()
+
+	// streamon is acknowledged but no video is sent
+	if err := d.StartVideo(); !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected %s, got %v", ErrTimeout, err)
+	}
+
+	// Send video when streamon is received
+	c.mt.Lock()
+	h := c.h
+	c.h = func(cmd []byte) []byte {
+		if string(cmd) == "streamon" {
+			if _, err := v.conn.Write(append([]byte{}, videoStartCode...)); err != nil {
+				t.Error(fmt.Errorf("test: writing video datagram failed: %w", err))
+			}
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+	if err := d.StartVideo(); err != nil {
+		t.Error(fmt.Errorf("test: starting video failed: %w", err))
+	}
+
+	// Context is canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := d.StartVideoContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %s, got %v", context.Canceled, err)
+	}
+
+	// Context expires while waiting for the response
+	c.mt.Lock()
+	c.timeout = true
+	c.mt.Unlock()
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := d.StopVideoContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %s, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestVideoOptional(t *testing.T) {
+	// Occupy the video port
+	laddr, err := net.ResolveUDPAddr("udp4", videoAddr)
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: creating laddr failed: %w", err))
+	}
+	conn, err := net.ListenUDP("udp4", laddr)
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: listening failed: %w", err))
+	}
+	defer conn.Close()
+
+	for _, optional := range []bool{false, true} {
+		t.Run(fmt.Sprintf("optional %t", optional), func(t *testing.T) {
+			// Set up
+			d, c, s, v, err := setup(t, WithVideoOptional(optional))
+			if err != nil {
+				t.Fatal(fmt.Errorf("test: setting up failed: %w", err))
+			}
+			defer func() {
+				d.Close()
+				c.close()
+				s.close()
+				v.close()
+			}()
+
+			// Start
+			err = d.Start()
+			if !optional {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(fmt.Errorf("test: starting the drone failed: %w", err))
+			}
+
+			// Cmds should still work
+			if err = d.Up(1); err != nil {
+				t.Error(fmt.Errorf("test: sending cmd failed: %w", err))
+			}
+
+			// Video should be unavailable
+			if err = d.StartVideo(); !errors.Is(err, ErrVideoUnavailable) {
+				t.Errorf("expected %s, got %v", ErrVideoUnavailable, err)
+			}
+		})
+	}
+}
+
+func TestVideoStartedStopped(t *testing.T) {
+	// Set up and start
+	d, _, _, v, teardown := setupAndStart(t, WithVideoIdleTimeout(50*time.Millisecond))
+	defer teardown()
+
+	// Handle events
+	es := make(chan string, 10)
+	d.On(VideoStartedEvent, func(interface{}) { es <- VideoStartedEvent })
+	d.On(VideoStoppedEvent, func(interface{}) { es <- VideoStoppedEvent })
+	next := func() string {
+		select {
+		case e := <-es:
+			return e
+		case <-time.After(time.Second):
+			return ""
+		}
+	}
+
+	// Write datagrams
+	write := func() {
+		if _, err := v.conn.Write(append([]byte{}, videoStartCode...)); err != nil {
+			t.Fatal(fmt.Errorf("test: writing video datagram failed: %w", err))
+		}
+	}
+	n := time.Now()
+	for i := 0; i < 3; i++ {
+		write()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Started once, then stopped after the idle timeout
+	if e, g := VideoStartedEvent, next(); e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+	if e, g := VideoStoppedEvent, next(); e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+	if e, g := 70*time.Millisecond, time.Since(n); g < e {
+		t.Errorf("expected stopped after at least %s, got %s", e, g)
+	}
+
+	// Stopping video dispatches the event right away
+	write()
+	if e, g := VideoStartedEvent, next(); e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+	if err := d.StopVideo(); err != nil {
+		t.Error(fmt.Errorf("test: stopping video failed: %w", err))
+	}
+	if e, g := VideoStoppedEvent, next(); e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+	select {
+	case e := <-es:
+		t.Errorf("expected no event, got %s", e)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestConnected(t *testing.T) {
+	// Set up
+	d, c, s, v, err := setup(t)
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: setting up failed: %w", err))
+	}
+
+	// Make sure to close everything properly
+	defer func() {
+		c.close()
+		s.close()
+		v.close()
+	}()
+
+	// Handle events
+	connected := make(chan bool, 2)
+	d.On(ConnectEvent, func(interface{}) { connected <- true })
+	d.On(DisconnectEvent, func(interface{}) { connected <- false })
+
+	// Not connected yet
+	if d.Connected() {
+		t.Error("expected connected == false, got true")
+	}
+
+	// Start
+	if err = d.Start(); err != nil {
+		t.Fatal(fmt.Errorf("test: starting the drone failed: %w", err))
+	}
+	if !d.Connected() {
+		t.Error("expected connected == true, got false")
+	}
+	if !<-connected {
+		t.Error("expected connect event")
+	}
+
+	// Close
+	d.Close()
+	if d.Connected() {
+		t.Error("expected connected == false, got true")
+	}
+	if <-connected {
+		t.Error("expected disconnect event")
+	}
+
+	// Handlers are removed on close
+	if err = d.Start(); err != nil {
+		t.Fatal(fmt.Errorf("test: starting the drone failed: %w", err))
+	}
+	<-d.e.processed()
+	d.Close()
+	if l := len(connected); l > 0 {
+		t.Errorf("expected no events, got %d", l)
+	}
+}
+
+func TestWaitForConnection(t *testing.T) {
+	// Set up
+	d, c, s, v, err := setup(t)
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: setting up failed: %w", err))
+	}
+
+	// Make sure to close everything properly
+	defer func() {
+		c.close()
+		s.close()
+		v.close()
+	}()
+
+	// Context expires
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err = d.WaitForConnection(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %s, got %s", context.DeadlineExceeded, err)
+	}
+
+	// Wait in a goroutine
+	errs := make(chan error)
+	go func() { errs <- d.WaitForConnection(context.Background()) }()
+
+	// Start
+	time.Sleep(10 * time.Millisecond)
+	if err = d.Start(); err != nil {
+		t.Fatal(fmt.Errorf("test: starting the drone failed: %w", err))
+	}
+	defer d.Close()
+	select {
+	case err = <-errs:
+		if err != nil {
+			t.Errorf("expected no error, got %s", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected connection")
+	}
+
+	// Already connected
+	if err = d.WaitForConnection(context.Background()); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	}
+}
+
+func TestWaitForState(t *testing.T) {
+	// Set up and start
+	d, _, s, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Context expires
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := d.WaitForState(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %s, got %s", context.DeadlineExceeded, err)
+	}
+
+	// Wait in a goroutine
+	type result struct {
+		err error
+		s   State
+	}
+	rs := make(chan result)
+	go func() {
+		s, err := d.WaitForState(context.Background())
+		rs <- result{err: err, s: s}
+	}()
+
+	// Write state
+	time.Sleep(10 * time.Millisecond)
+	if _, err := s.conn.Write([]byte(strState)); err != nil {
+		t.Fatal(fmt.Errorf("test: writing state failed: %w", err))
+	}
+	select {
+	case r := <-rs:
+		if r.err != nil {
+			t.Errorf("expected no error, got %s", r.err)
+		} else if e := 18; r.s.Battery != e {
+			t.Errorf("expected battery %d, got %d", e, r.s.Battery)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected state")
+	}
+
+	// State has already been received
+	if g, err := d.WaitForState(context.Background()); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	} else if e := 18; g.Battery != e {
+		t.Errorf("expected battery %d, got %d", e, g.Battery)
+	}
+}
+
+func TestStateChan(t *testing.T) {
+	// Set up and start
+	d, _, s, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Get channel
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := d.StateChan(ctx)
+
+	// Consume two states
+	for i := 0; i < 2; i++ {
+		if _, err := s.conn.Write([]byte(strState)); err != nil {
+			t.Fatal(fmt.Errorf("test: writing state failed: %w", err))
+		}
+		select {
+		case st := <-c:
+			if e := 18; st.Battery != e {
+				t.Errorf("expected battery %d, got %d", e, st.Battery)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected state")
+		}
+	}
+
+	// Channel is closed once the context is done
+	cancel()
+	select {
+	case _, ok := <-c:
+		if ok {
+			t.Error("expected channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Error("expected channel to be closed")
+	}
+}
+
+func TestCommandAddr(t *testing.T) {
+	// Set up
+	_, c, s, v, err := setup(t)
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: setting up failed: %w", err))
+	}
+
+	// Make sure to close everything properly
+	defer func() {
+		c.close()
+		s.close()
+		v.close()
+	}()
+
+	// Update defaults
+	a := cmdAddr
+	cmdAddr = "127.0.0.1:1"
+	defer func() { cmdAddr = a }()
+
+	// Start
+	d := New(nil, WithCommandAddr(c.conn.LocalAddr().String()))
+	if err = d.Start(); err != nil {
+		t.Fatal(fmt.Errorf("test: starting the drone failed: %w", err))
+	}
+	defer d.Close()
+
+	// Cmds should be sent to the provided addr
+	if e, g := []string{"command", "sdk?", "sn?"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+}
+
+func TestNetwork(t *testing.T) {
+	// Set up and start
+	d, _, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Connections should be IPv4 by default
+	d.mco.Lock()
+	for _, conn := range []*net.UDPConn{d.cmdConn, d.stateConn, d.videoConn} {
+		if a := conn.LocalAddr().(*net.UDPAddr); a.IP.To4() == nil {
+			t.Errorf("expected an IPv4 local addr, got %s", a)
+		}
+	}
+	d.mco.Unlock()
+
+	// Invalid network
+	if err := New(nil, WithNetwork("tcp")).Start(); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestConnectRetries(t *testing.T) {
+	// Set up
+	d, c, s, v, err := setup(t, WithConnectRetries(3, 20*time.Millisecond))
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: setting up failed: %w", err))
+	}
+
+	// Make sure to close everything properly
+	defer func() {
+		c.close()
+		s.close()
+		v.close()
+	}()
+
+	// Ignore the first "command" cmd
+	c.mt.Lock()
+	h := c.h
+	var ignored bool
+	c.h = func(cmd []byte) []byte {
+		if string(cmd) == "command" && !ignored {
+			ignored = true
+			return nil
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+
+	// Start
+	if err = d.Start(); err != nil {
+		t.Fatal(fmt.Errorf("test: starting the drone failed: %w", err))
+	}
+	defer d.Close()
+
+	// Check
+	if e, g := []string{"command", "command", "sdk?", "sn?"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+	if !d.Connected() {
+		t.Error("expected connected == true, got false")
+	}
+}
+
+func TestConnectRetriesDeadline(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t, WithConnectRetries(5, 30*time.Millisecond))
+	defer teardown()
+
+	// Don't respond
+	c.mt.Lock()
+	c.timeout = true
+	c.mt.Unlock()
+
+	// Retries stop once the deadline is exceeded
+	n := time.Now()
+	if err := d.command(n.Add(50 * time.Millisecond)); !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected %s, got %v", ErrTimeout, err)
+	}
+	if e, g := 500*time.Millisecond, time.Since(n); g > e {
+		t.Errorf("expected command to return within %s, got %s", e, g)
+	}
+	if e, g := []string{"command", "sdk?", "sn?", "command", "command"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+
+	// Nothing is sent once the deadline is exceeded
+	if err := d.command(time.Now().Add(-time.Millisecond)); !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected %s, got %v", ErrTimeout, err)
+	}
+	if e, g := 5, len(c.received()); g != e {
+		t.Errorf("expected %d cmds, got %d", e, g)
+	}
+}
+
+func TestCommandRetries(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t, WithCommandRetries(1), WithTimeouts(Timeouts{
+		Move:    50 * time.Millisecond,
+		TakeOff: 50 * time.Millisecond,
+	}))
+	defer teardown()
+
+	// Drop the first response of each cmd
+	c.mt.Lock()
+	h := c.h
+	seen := make(map[string]bool)
+	c.h = func(cmd []byte) []byte {
+		if !seen[string(cmd)] {
+			seen[string(cmd)] = true
+			return nil
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+
+	// Movement cmds are retried
+	if err := d.Up(1); err != nil {
+		t.Error(fmt.Errorf("test: moving up failed: %w", err))
+	}
+
+	// Take off is not retried
+	if err := d.TakeOff(); !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected %s, got %v", ErrTimeout, err)
+	}
+	if e, g := []string{"command", "sdk?", "sn?", "up 1", "up 1", "takeoff"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+
+	// Classification
+	for c, e := range map[string]bool{
+		"battery?":   true,
+		"command":    false,
+		"emergency":  false,
+		"flip l":     false,
+		"go 1 2 3 4": true,
+		"land":       false,
+		"speed 10":   true,
+		"takeoff":    false,
+	} {
+		if g := retriableCmd(c); g != e {
+			t.Errorf("%s: expected %v, got %v", c, e, g)
+		}
+	}
+}
+
+func TestCommandRetriesLateResponse(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t, WithCommandRetries(1), WithTimeouts(Timeouts{
+		Move:    50 * time.Millisecond,
+		TakeOff: 50 * time.Millisecond,
+	}))
+	defer teardown()
+
+	// Answer the first "up 1" late, and "down 1" with an error
+	c.mt.Lock()
+	h := c.h
+	var ups int
+	c.h = func(cmd []byte) []byte {
+		switch string(cmd) {
+		case "down 1":
+			return []byte("error Motor stop")
+		case "up 1":
+			if ups++; ups == 1 {
+				go func() {
+					time.Sleep(80 * time.Millisecond)
+					c.conn.Write([]byte("ok"))
+				}()
+				return nil
+			}
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+
+	// The late response is received by the retried cmd, not by the next one
+	if err := d.Up(1); err != nil {
+		t.Error(fmt.Errorf("test: moving up failed: %w", err))
+	}
+	var e *DroneError
+	if err := d.Down(1); !errors.As(err, &e) {
+		t.Errorf("expected %T, got %v", e, err)
+	}
+
+	// Answer "takeoff" late
+	c.mt.Lock()
+	c.h = func(cmd []byte) []byte {
+		switch string(cmd) {
+		case "down 1":
+			return []byte("error Motor stop")
+		case "takeoff":
+			go func() {
+				time.Sleep(80 * time.Millisecond)
+				c.conn.Write([]byte("ok"))
+			}()
+			return nil
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+
+	// The cmd is abandoned
+	if err := d.TakeOff(); !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected %s, got %v", ErrTimeout, err)
+	}
+
+	// Wait for the late response to be dropped
+	for deadline := time.Now().Add(time.Second); ; {
+		d.mw.Lock()
+		l := len(d.waiting)
+		d.mw.Unlock()
+		if l == 0 {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatalf("expected late response to be dropped, %d cmds still waiting", l)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// The late response is not received by the next cmd
+	if err := d.Down(1); !errors.As(err, &e) {
+		t.Errorf("expected %T, got %v", e, err)
+	}
+}
+
+func TestConnectTimeout(t *testing.T) {
+	// Set up
+	d, c, s, v, err := setup(t, WithConnectTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: setting up failed: %w", err))
+	}
+
+	// Make sure to close everything properly
+	defer func() {
+		c.close()
+		s.close()
+		v.close()
+	}()
+
+	// Don't respond
+	c.mt.Lock()
+	c.timeout = true
+	c.mt.Unlock()
+
+	// Start should return once the deadline is exceeded instead of after the default timeout
+	n := time.Now()
+	err = d.Start()
+	defer d.Close()
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected %s, got %v", ErrTimeout, err)
+	}
+	if e, g := time.Second, time.Since(n); g > e {
+		t.Errorf("expected Start to return within %s, got %s", e, g)
+	}
+	if d.Connected() {
+		t.Error("expected connected == false, got true")
+	}
+}
+
+func TestManualHandshake(t *testing.T) {
+	// Auto handshake
+	d, c, _, _, teardown := setupAndStart(t)
+	if e, g := []string{"command", "sdk?", "sn?"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+	if err := d.EnterSDKMode(); err != nil {
+		t.Error(fmt.Errorf("test: entering sdk mode failed: %w", err))
+	}
+	if e, g := []string{"command", "sdk?", "sn?", "command"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+	teardown()
+
+	// Manual handshake
+	d, c, _, _, teardown = setupAndStart(t, WithManualHandshake(true))
+	defer teardown()
+	if !d.Connected() {
+		t.Error("expected connected == true, got false")
+	}
+	if g := c.received(); len(g) > 0 {
+		t.Errorf("expected no cmds, got %+v", g)
+	}
+	if err := d.EnterSDKMode(); err != nil {
+		t.Error(fmt.Errorf("test: entering sdk mode failed: %w", err))
+	}
+	if e, g := []string{"command", "sdk?", "sn?"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+
+	// Handshake failure is returned
+	c.mt.Lock()
+	h := c.h
+	c.h = func(cmd []byte) []byte {
+		if string(cmd) == "command" {
+			return []byte("error")
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+	var de *DroneError
+	if err := d.EnterSDKMode(); !errors.As(err, &de) {
+		t.Errorf("expected DroneError, got %v", err)
+	}
+}
+
+func TestMinTakeoffBattery(t *testing.T) {
+	// No state yet: battery is queried
+	d, c, s, _, teardown := setupAndStart(t, WithMinTakeoffBattery(20))
+	defer teardown()
+	if err := d.TakeOff(); err != nil {
+		t.Error(fmt.Errorf("test: taking off failed: %w", err))
+	}
+	if e, g := []string{"command", "sdk?", "sn?", "battery?", "takeoff"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+
+	// Low battery state
+	if _, err := s.conn.Write([]byte(strState)); err != nil {
+		t.Fatal(fmt.Errorf("test: writing state failed: %w", err))
+	}
+	for d.State().Battery == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if err := d.TakeOff(); !errors.Is(err, ErrBatteryTooLow) {
+		t.Errorf("expected %s, got %s", ErrBatteryTooLow, err)
+	}
+	if e, g := 5, len(c.received()); e != g {
+		t.Errorf("expected %d cmds, got %d", e, g)
+	}
+}
+
+func TestStateAge(t *testing.T) {
+	// Set up and start
+	d, _, s, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// No state yet
+	if d.StateFresh(time.Hour) {
+		t.Error("expected fresh == false, got true")
+	}
+
+	// Write state
+	if _, err := s.conn.Write([]byte(strState)); err != nil {
+		t.Fatal(fmt.Errorf("test: writing state failed: %w", err))
+	}
+	for n := time.Now(); !d.StateFresh(time.Hour); time.Sleep(time.Millisecond) {
+		if time.Since(n) > time.Second {
+			t.Fatal("expected fresh state")
+		}
+	}
+
+	// Wait for the state to become stale
+	time.Sleep(5 * time.Millisecond)
+	if d.StateFresh(time.Millisecond) {
+		t.Error("expected fresh == false, got true")
+	}
+	if g := d.StateAge(); g < 5*time.Millisecond || g > time.Hour {
+		t.Errorf("expected age between 5ms and 1h, got %s", g)
+	}
+}
+
+func TestLandOnClose(t *testing.T) {
+	// Update defaults
+	lt := landOnCloseTimeout
+	landOnCloseTimeout = 20 * time.Millisecond
+	defer func() { landOnCloseTimeout = lt }()
+
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t, WithLandOnClose(true))
+	defer teardown()
+
+	// Close
+	d.Close()
+	cmds := c.received()
+	if e, g := "land", cmds[len(cmds)-1]; e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+
+	// Close shouldn't hang when the drone doesn't respond
+	if err := d.Start(); err != nil {
+		t.Fatal(fmt.Errorf("test: starting the drone failed: %w", err))
+	}
+	c.mt.Lock()
+	c.timeout = true
+	c.mt.Unlock()
+	n := time.Now()
+	d.Close()
+	if g := time.Since(n); g > time.Second {
+		t.Errorf("expected close to take less than 1s, took %s", g)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Slow cmd
+	c.mt.Lock()
+	h := c.h
+	c.h = func(cmd []byte) []byte {
+		if string(cmd) == "up 1" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+
+	// Send slow cmd
+	errUp := make(chan error, 1)
+	go func() { errUp <- d.Up(1) }()
+	for len(d.PendingCommands()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Shut down
+	errShutdown := make(chan error, 1)
+	go func() { errShutdown <- d.Shutdown(context.Background()) }()
+	for {
+		d.mc.Lock()
+		shuttingDown := d.shuttingDown
+		d.mc.Unlock()
+		if shuttingDown {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// New cmds should be rejected
+	if err := d.Down(1); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("expected %s, got %v", ErrShuttingDown, err)
+	}
+
+	// Slow cmd should complete before connections are closed
+	if err := <-errUp; err != nil {
+		t.Error(fmt.Errorf("test: sending cmd failed: %w", err))
+	}
+	if err := <-errShutdown; err != nil {
+		t.Error(fmt.Errorf("test: shutting down failed: %w", err))
+	}
+	if d.Connected() {
+		t.Error("expected connected == false, got true")
+	}
+
+	// Shutdown shouldn't wait past the context when the drone doesn't respond
+	if err := d.Start(); err != nil {
+		t.Fatal(fmt.Errorf("test: starting the drone failed: %w", err))
+	}
+	c.mt.Lock()
+	c.timeout = true
+	c.mt.Unlock()
+	go d.Up(1)
+	for len(d.PendingCommands()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := d.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %s, got %v", context.DeadlineExceeded, err)
+	}
+	if d.Connected() {
+		t.Error("expected connected == false, got true")
+	}
+}
+
+func TestReconnect(t *testing.T) {
+	// Set up
+	d, c, s, v, err := setup(t)
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: setting up failed: %w", err))
+	}
+
+	// Make sure to close everything properly
+	defer func() {
+		c.close()
+		s.close()
+		v.close()
+	}()
+
+	// Connect, disconnect and reconnect
+	for i := 0; i < 2; i++ {
+		// Connect
+		if err = d.Connect(); err != nil {
+			t.Fatal(fmt.Errorf("test: connecting to the drone failed: %w", err))
+		}
+
+		// Send cmd
+		if err = d.Up(1); err != nil {
+			t.Error(fmt.Errorf("test: sending cmd failed: %w", err))
+		}
+
+		// Disconnect
+		d.Disconnect()
+	}
+
+	// Check
+	if e, g := []string{"command", "sdk?", "sn?", "up 1", "command", "sdk?", "sn?", "up 1"}, c.received(); !reflect.DeepEqual(g, e) {
+		t.Errorf("expected cmds %+v, got %+v", e, g)
+	}
+}
+
+func TestConnectCloseCycles(t *testing.T) {
+	// Set up
+	_, c, s, v, err := setup(t)
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: setting up failed: %w", err))
+	}
+
+	// Make sure to close everything properly
+	defer func() {
+		c.close()
+		s.close()
+		v.close()
+	}()
+
+	// Sockets should be released once closed, even for another drone
+	for i := 0; i < 10; i++ {
+		// Connect
+		d := New(nil)
+		if err = d.Connect(); err != nil {
+			t.Fatal(fmt.Errorf("test: connecting to the drone failed at cycle %d: %w", i+1, err))
+		}
+
+		// Send cmd
+		if err = d.Up(1); err != nil {
+			t.Error(fmt.Errorf("test: sending cmd failed: %w", err))
+		}
+
+		// Disconnect
+		d.Disconnect()
+	}
+}
+
+func TestStillClosing(t *testing.T) {
+	// Update defaults
+	i := closeWaitTimeout
+	closeWaitTimeout = 10 * time.Millisecond
+	defer func() { closeWaitTimeout = i }()
+
+	// Set up and start
+	d, _, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Simulate a stuck goroutine
+	d.wg.Add(1)
+
+	// Close should return anyway
+	n := time.Now()
+	d.Close()
+	if g := time.Since(n); g > time.Second {
+		t.Errorf("expected close to be bounded, took %s", g)
+	}
+
+	// Start should fail until the goroutine is done
+	if err := d.Start(); !errors.Is(err, ErrStillClosing) {
+		t.Errorf("expected %s, got %v", ErrStillClosing, err)
+	}
+	d.wg.Done()
+	for n := time.Now(); ; time.Sleep(time.Millisecond) {
+		err := d.Start()
+		if err == nil {
+			break
+		} else if !errors.Is(err, ErrStillClosing) || time.Since(n) > time.Second {
+			t.Fatal(fmt.Errorf("test: starting the drone failed: %w", err))
+		}
+	}
+}
+
+func TestAutoReconnect(t *testing.T) {
+	// Update defaults
+	dt, ret := defaultTimeout, reconnectErrorThreshold
+	defaultTimeout, reconnectErrorThreshold = 100*time.Millisecond, 1
+	defer func() { defaultTimeout, reconnectErrorThreshold = dt, ret }()
+
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t, WithAutoReconnect(3, 10*time.Millisecond))
+	defer teardown()
+
+	// Handle events
+	reconnected := make(chan bool, 1)
+	d.On(ReconnectedEvent, func(interface{}) { reconnected <- true })
+
+	// Kill cmd dialer and replace it with a new one
+	c.close()
+	c2 := newDialer(t, "127.0.0.1:", respAddr)
+	c2.h = c.h
+	if err := c2.start(); err != nil {
+		t.Fatal(fmt.Errorf("test: starting cmd dialer failed: %w", err))
+	}
+	defer c2.close()
+	cmdAddr = c2.conn.LocalAddr().String()
+
+	// Sending a cmd to the killed dialer should make reading the response fail
+	if err := d.command(time.Time{}); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	// Wait for re-dial
+	select {
+	case <-reconnected:
+	case <-time.After(time.Second):
+		t.Fatal("expected reconnected event")
+	}
+
+	// Send cmd to the new dialer
+	if err := d.Up(1); err != nil {
+		t.Error(fmt.Errorf("test: sending cmd failed: %w", err))
+	}
+	if e, g := []string{"up 1"}, c2.received(); !reflect.DeepEqual(g, e) {
+		t.Errorf("expected cmds %+v, got %+v", e, g)
+	}
+}
+
+func TestReconnectAddr(t *testing.T) {
+	// Set up and start
+	d, c, s, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Handle events
+	reconnected := make(chan bool, 1)
+	d.On(ReconnectedEvent, func(interface{}) { reconnected <- true })
+
+	// Create a second cmd dialer, as if the drone had joined an access point
+	c.close()
+	c2 := newDialer(t, "127.0.0.1:", respAddr)
+	c2.h = c.h
+	if err := c2.start(); err != nil {
+		t.Fatal(fmt.Errorf("test: starting cmd dialer failed: %w", err))
+	}
+	defer c2.close()
+
+	// Reconnect
+	if err := d.Reconnect(c2.conn.LocalAddr().String()); err != nil {
+		t.Fatal(fmt.Errorf("test: reconnecting failed: %w", err))
+	}
+	select {
+	case <-reconnected:
+	case <-time.After(time.Second):
+		t.Fatal("expected reconnected event")
+	}
+
+	// Send cmd to the new dialer
+	if err := d.Up(1); err != nil {
+		t.Error(fmt.Errorf("test: sending cmd failed: %w", err))
+	}
+	if e, g := []string{"command", "up 1"}, c2.received(); !reflect.DeepEqual(g, e) {
+		t.Errorf("expected cmds %+v, got %+v", e, g)
+	}
+
+	// State connection has not been re-created
+	if _, err := s.conn.Write([]byte(strState)); err != nil {
+		t.Fatal(fmt.Errorf("test: writing state failed: %w", err))
+	}
+	for n := time.Now(); d.State().Battery == 0; time.Sleep(time.Millisecond) {
+		if time.Since(n) > time.Second {
+			t.Fatal("expected state")
+		}
+	}
+
+	// Not connected
+	d.Close()
+	if err := d.Reconnect(c2.conn.LocalAddr().String()); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("expected %s, got %v", ErrNotConnected, err)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	// Update defaults
+	dt := defaultTimeout
+	defaultTimeout = 100 * time.Millisecond
+	defer func() { defaultTimeout = dt }()
+
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Handle events
+	errs := make(chan error, 1)
+	d.On(ErrorEvent, ErrorEventHandler(func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}))
+
+	// Sending a cmd to the killed dialer should make reading the response fail
+	c.close()
+	if err := d.command(time.Time{}); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	// Wait for error event
+	select {
+	case <-errs:
+	case <-time.After(time.Second):
+		t.Fatal("expected error event")
+	}
+
+	// Sleep duration should be capped
+	if e, g := readErrorMaxSleep, readErrorSleep(1000); e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+}
+
+func TestOverlappingCmds(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Delay the speed? response
+	c.mt.Lock()
+	h := c.h
+	c.h = func(cmd []byte) []byte {
+		if string(cmd) == "speed?" {
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				if _, err := c.conn.Write(h(cmd)); err != nil {
+					t.Log(fmt.Errorf("test: writing failed: %w", err))
+				}
+			}()
+			return nil
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+
+	// Send query cmd
+	type result struct {
+		err   error
+		speed int
+	}
+	rs := make(chan result)
+	go func() {
+		speed, err := d.Speed()
+		rs <- result{err: err, speed: speed}
+	}()
+
+	// Wait for the query cmd to be received
+	for len(c.received()) < 4 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Check pending cmds
+	if e, g := []string{"speed?"}, d.PendingCommands(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+
+	// Send priority cmd while the query cmd is still waiting for its response
+	if err := d.Land(); err != nil {
+		t.Error(fmt.Errorf("test: landing failed: %w", err))
+	}
+
+	// Check query cmd
+	if r := <-rs; r.err != nil {
+		t.Error(fmt.Errorf("test: getting speed failed: %w", r.err))
+	} else if r.speed != 100 {
+		t.Errorf("expected 100, got %d", r.speed)
+	}
+	if g := d.PendingCommands(); len(g) > 0 {
+		t.Errorf("expected no pending cmds, got %+v", g)
+	}
+}
+
+func TestOverlappingPriorityCmds(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Delay the up 1 response and make land fail
+	c.mt.Lock()
+	h := c.h
+	c.h = func(cmd []byte) []byte {
+		switch string(cmd) {
+		case "land":
+			return []byte("error Motor stop")
+		case "up 1":
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				if _, err := c.conn.Write(h(cmd)); err != nil {
+					t.Log(fmt.Errorf("test: writing failed: %w", err))
+				}
+			}()
+			return nil
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+
+	// Send move cmd
+	errs := make(chan error)
+	go func() { errs <- d.Up(1) }()
+
+	// Wait for the move cmd to be received
+	for len(c.received()) < 4 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Responses are handed in order: the move gets the land's error whereas the land gets the move's "ok"
+	if err := d.Land(); err != nil {
+		t.Error(fmt.Errorf("test: landing failed: %w", err))
+	}
+	var de *DroneError
+	if err := <-errs; !errors.As(err, &de) {
+		t.Errorf("expected DroneError, got %v", err)
+	} else if e, g := "error Motor stop", de.Raw; e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+	if e, g := []string{"command", "sdk?", "sn?", "up 1", "land"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t, WithTimeouts(Timeouts{Default: 20 * time.Millisecond}))
+	defer teardown()
+
+	// Drone error
+	c.mt.Lock()
+	h := c.h
+	c.h = func(cmd []byte) []byte {
+		switch string(cmd) {
+		case "speed 1", "speed?":
+			return []byte("error Not joystick")
+		case "speed 2":
+			return nil
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+	for _, v := range []struct {
+		cmd string
+		f   func() error
+	}{
+		{cmd: "speed 1", f: func() error { return d.SetSpeed(1) }},
+		{cmd: "speed?", f: func() error { _, err := d.Speed(); return err }},
+	} {
+		err := v.f()
+		var de *DroneError
+		if !errors.As(err, &de) {
+			t.Errorf("expected DroneError, got %s", err)
+		} else if e, g := "error Not joystick", de.Raw; e != g {
+			t.Errorf("expected %s, got %s", e, g)
+		}
+		var ce *CommandError
+		if !errors.As(err, &ce) {
+			t.Errorf("expected CommandError, got %s", err)
+		} else if ce.Command != v.cmd {
+			t.Errorf("expected %s, got %s", v.cmd, ce.Command)
+		}
+	}
+
+	// Timeout
+	err := d.SetSpeed(2)
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected %s, got %s", ErrTimeout, err)
+	}
+	var ce *CommandError
+	if !errors.As(err, &ce) {
+		t.Errorf("expected CommandError, got %s", err)
+	} else if e := "speed 2"; ce.Command != e {
+		t.Errorf("expected %s, got %s", e, ce.Command)
+	}
+
+	// Validation errors happen before any cmd is sent
+	if err = d.Flip("x"); errors.As(err, &ce) {
+		t.Errorf("expected no CommandError, got %s", err)
+	}
+
+	// Transport error
+	d.mco.Lock()
+	d.cmdConn.Close()
+	d.mco.Unlock()
+	var te *TransportError
+	if err := d.SetSpeed(1); !errors.As(err, &te) {
+		t.Errorf("expected TransportError, got %s", err)
+	}
+}
+
+func TestSetSpeedVerified(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Clamp speed
+	c.mt.Lock()
+	h := c.h
+	c.h = func(cmd []byte) []byte {
+		switch string(cmd) {
+		case "speed 100", "speed 120":
+			return []byte("ok")
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+
+	// Speed is applied
+	if err := d.SetSpeedVerified(100); err != nil {
+		t.Error(fmt.Errorf("test: setting speed failed: %w", err))
+	}
+
+	// Speed is clamped
+	var se *SpeedMismatchError
+	if err := d.SetSpeedVerified(120); !errors.As(err, &se) {
+		t.Errorf("expected SpeedMismatchError, got %s", err)
+	} else if e := (SpeedMismatchError{Applied: 100, Requested: 120}); *se != e {
+		t.Errorf("expected %+v, got %+v", e, *se)
+	}
+}
+
+func TestSetWifi(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Handle events
+	ssids := make(chan string, 3)
+	d.On(WifiChangedEvent, WifiChangedEventHandler(func(ssid string) { ssids <- ssid }))
+
+	// Reject ssid cmds
+	c.mt.Lock()
+	h := c.h
+	c.h = func(cmd []byte) []byte {
+		switch string(cmd) {
+		case "wifi ssid1 password", "wifi ssid2 password", "wifi ssid3 password":
+			return []byte("error")
+		case "ssid?":
+			return []byte("ssid2")
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+
+	// Success
+	if err := d.SetWifi("1", "2"); err != nil {
+		t.Error(fmt.Errorf("test: setting wifi failed: %w", err))
+	}
+
+	// SSID can't be read back since capabilities have not been probed
+	var we *WifiError
+	var de *DroneError
+	if err := d.SetWifi("ssid1", "password"); !errors.As(err, &we) || !errors.As(err, &de) {
+		t.Errorf("expected WifiError and DroneError, got %s", err)
+	} else if we.Part != "" {
+		t.Errorf("expected empty part, got %s", we.Part)
+	}
+
+	// Probe capabilities
+	d.mcp.Lock()
+	d.caps = Capabilities{Probed: true, SDKVersion: "30", VideoConfig: true}
+	d.mcp.Unlock()
+
+	// SSID has changed but password has not
+	if err := d.SetWifi("ssid2", "password"); !errors.As(err, &we) {
+		t.Errorf("expected WifiError, got %s", err)
+	} else if e := WifiPartPassword; we.Part != e {
+		t.Errorf("expected %s, got %s", e, we.Part)
+	}
+
+	// SSID has not changed
+	if err := d.SetWifi("ssid3", "password"); !errors.As(err, &we) {
+		t.Errorf("expected WifiError, got %s", err)
+	} else if e := WifiPartSSID; we.Part != e {
+		t.Errorf("expected %s, got %s", e, we.Part)
+	}
+
+	// Only changed SSIDs should be dispatched
+	var g []string
+	for len(g) < 2 {
+		select {
+		case s := <-ssids:
+			g = append(g, s)
+		case <-time.After(time.Second):
+			t.Fatalf("expected 2 ssids, got %+v", g)
+		}
+	}
+	if e := []string{"1", "ssid2"}; !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+	select {
+	case s := <-ssids:
+		t.Errorf("unexpected ssid %s", s)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestResponseEvent(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Handle events
+	resps := make(chan string, 3)
+	d.On(ResponseEvent, ResponseEventHandler(func(resp string) { resps <- resp }))
+
+	// Error response
+	c.mt.Lock()
+	h := c.h
+	c.h = func(cmd []byte) []byte {
+		if string(cmd) == "speed 1" {
+			return []byte("error Not joystick")
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+
+	// Send cmds
+	if err := d.Up(1); err != nil {
+		t.Error(fmt.Errorf("test: sending cmd failed: %w", err))
+	}
+	if _, err := d.Battery(); err != nil {
+		t.Error(fmt.Errorf("test: querying battery failed: %w", err))
+	}
+	if err := d.SetSpeed(1); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	// Every response should be dispatched
+	var g []string
+	for len(g) < 3 {
+		select {
+		case r := <-resps:
+			g = append(g, r)
+		case <-time.After(time.Second):
+			t.Fatalf("expected 3 responses, got %+v", g)
+		}
+	}
+	if e := []string{"ok", "50", "error Not joystick"}; !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+}
+
+func TestMove(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Respond to go cmd
+	c.mt.Lock()
+	h := c.h
+	c.h = func(cmd []byte) []byte {
+		if string(cmd) == "go -100 50 0 30" {
+			return []byte("ok")
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+
+	// Move
+	if err := d.Move(-100, 50, 0, 30); err != nil {
+		t.Error(fmt.Errorf("test: moving failed: %w", err))
+	}
+	if e, g := []string{"command", "sdk?", "sn?", "go -100 50 0 30"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+
+	// Invalid
+	if err := d.Move(0, 0, 0, 30); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected %s, got %v", ErrInvalidArgument, err)
+	}
+}
+
+func TestValidateCurve(t *testing.T) {
+	for _, v := range []struct {
+		err  bool
+		name string
+		ps   [6]int
+		r    float64
+	}{
+		{name: "semicircle", ps: [6]int{100, 100, 0, 200, 0, 0}, r: 100},
+		{name: "vertical semicircle", ps: [6]int{0, 50, 50, 0, 100, 0}, r: 50},
+		{name: "right angle", ps: [6]int{0, 300, 0, 400, 300, 0}, r: 250},
+		{name: "3d", ps: [6]int{100, 0, 100, 0, 100, 100}, r: 81.65},
+		{name: "radius too small", ps: [6]int{20, 20, 0, 40, 0, 0}, r: 20, err: true},
+		{name: "radius too large", ps: [6]int{250, 1, 0, 500, 0, 0}, r: 31250.5, err: true},
+		{name: "aligned", ps: [6]int{100, 100, 100, 200, 200, 200}, err: true},
+		{name: "same point", ps: [6]int{100, 100, 0, 100, 100, 0}, err: true},
+		{name: "out of bounds", ps: [6]int{100, 100, 0, 600, 0, 0}, err: true},
+	} {
+		// Check radius
+		if v.r > 0 {
+			r, ok := curveRadius(float64(v.ps[0]), float64(v.ps[1]), float64(v.ps[2]), float64(v.ps[3]), float64(v.ps[4]), float64(v.ps[5]))
+			if !ok {
+				t.Errorf("%s: expected radius", v.name)
+			} else if math.Abs(r-v.r) > 0.01 {
+				t.Errorf("%s: expected radius %f, got %f", v.name, v.r, r)
+			}
+		}
+
+		// Validate
+		err := ValidateCurve(v.ps[0], v.ps[1], v.ps[2], v.ps[3], v.ps[4], v.ps[5])
+		if v.err && !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("%s: expected %s, got %v", v.name, ErrInvalidArgument, err)
+		} else if !v.err && err != nil {
+			t.Errorf("%s: expected no error, got %s", v.name, err)
+		}
+	}
+}
+
+func TestParseFlipDirection(t *testing.T) {
+	for i, e := range map[string]FlipDirection{
+		"b":       FlipBack,
+		"forward": FlipForward,
+		"Left":    FlipLeft,
+		"r":       FlipRight,
+	} {
+		if g, err := ParseFlipDirection(i); err != nil {
+			t.Error(fmt.Errorf("test: parsing %s failed: %w", i, err))
+		} else if g != e {
+			t.Errorf("%s: expected %s, got %s", i, e, g)
+		}
+	}
+	if _, err := ParseFlipDirection("x"); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected %s, got %v", ErrInvalidArgument, err)
+	}
+}
+
+func TestHandlerPanic(t *testing.T) {
+	// Set up and start
+	d, _, s, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Handle events
+	errs := make(chan error, 1)
+	d.On(ErrorEvent, ErrorEventHandler(func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}))
+	d.On(StateEvent, func(interface{}) { panic("test") })
+	states := make(chan State, 2)
+	d.On(StateEvent, StateEventHandler(func(s State) { states <- s }))
+
+	// Send states
+	for i := 0; i < 2; i++ {
+		if _, err := s.conn.Write([]byte(strState)); err != nil {
+			t.Fatal(fmt.Errorf("test: writing state failed: %w", err))
+		}
+
+		// Events should still be dispatched after the panic
+		select {
+		case <-states:
+		case <-time.After(time.Second):
+			t.Fatalf("expected state event %d", i+1)
+		}
+	}
+
+	// Panic should be dispatched as an error
+	select {
+	case err := <-errs:
+		if e, g := "astitello: state event handler panicked: test", err.Error(); e != g {
+			t.Errorf("expected %s, got %s", e, g)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected error event")
+	}
+}
+
+func TestOnPriority(t *testing.T) {
+	// Set up and start
+	d, _, s, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Handle events
+	m := &sync.Mutex{} // Locks hs
+	var hs []string
+	done := make(chan bool, 1)
+	for _, v := range []struct {
+		name     string
+		priority int
+	}{
+		{name: "ui1"},
+		{name: "safety", priority: 10},
+		{name: "ui2"},
+		{name: "log", priority: -1},
+		{name: "logger", priority: 5},
+	} {
+		name := v.name
+		d.OnPriority(StateEvent, v.priority, func(interface{}) {
+			m.Lock()
+			defer m.Unlock()
+			if hs = append(hs, name); len(hs) == 5 {
+				done <- true
+			}
+		})
+	}
+
+	// Send state
+	if _, err := s.conn.Write([]byte(strState)); err != nil {
+		t.Fatal(fmt.Errorf("test: writing state failed: %w", err))
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected state event")
+	}
+
+	// Check
+	m.Lock()
+	defer m.Unlock()
+	if e := []string{"safety", "logger", "ui1", "ui2", "log"}; !reflect.DeepEqual(e, hs) {
+		t.Errorf("expected %+v, got %+v", e, hs)
+	}
+}
+
+type testLogger struct {
+	m  *sync.Mutex // Locks ls
+	ls []string
+}
+
+func newTestLogger() *testLogger {
+	return &testLogger{m: &sync.Mutex{}}
+}
+
+func (l *testLogger) log(severity, format string, v ...interface{}) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	l.ls = append(l.ls, severity+": "+fmt.Sprintf(format, v...))
+}
+
+func (l *testLogger) Print(v ...interface{})                 { l.log("print", "%s", fmt.Sprint(v...)) }
+func (l *testLogger) Printf(format string, v ...interface{}) { l.log("print", format, v...) }
+func (l *testLogger) Debug(v ...interface{})                 { l.log("debug", "%s", fmt.Sprint(v...)) }
+func (l *testLogger) Debugf(format string, v ...interface{}) { l.log("debug", format, v...) }
+func (l *testLogger) Error(v ...interface{})                 { l.log("error", "%s", fmt.Sprint(v...)) }
+func (l *testLogger) Errorf(format string, v ...interface{}) { l.log("error", format, v...) }
+func (l *testLogger) Info(v ...interface{})                  { l.log("info", "%s", fmt.Sprint(v...)) }
+func (l *testLogger) Infof(format string, v ...interface{})  { l.log("info", format, v...) }
+
+func (l *testLogger) lines(prefix string) (ls []string) {
+	l.m.Lock()
+	defer l.m.Unlock()
+	for _, v := range l.ls {
+		if strings.HasPrefix(v, prefix) {
+			ls = append(ls, v)
+		}
+	}
+	return
+}
+
+func TestEventWorkers(t *testing.T) {
+	// Set up and start
+	d, _, s, _, teardown := setupAndStart(t, WithEventWorkers(2))
+	defer teardown()
+
+	// Handle events
+	release := make(chan bool)
+	defer close(release)
+	started := make(chan bool, 1)
+	d.On(ResponseEvent, func(interface{}) {
+		started <- true
+		<-release
+	})
+	states := make(chan bool, 1)
+	d.On(StateEvent, func(interface{}) { states <- true })
+
+	// Block a worker with a slow handler
+	if err := d.Up(1); err != nil {
+		t.Fatal(fmt.Errorf("test: sending cmd failed: %w", err))
+	}
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("expected response event")
+	}
+
+	// Other events should still flow
+	if _, err := s.conn.Write([]byte(strState)); err != nil {
+		t.Fatal(fmt.Errorf("test: writing state failed: %w", err))
+	}
+	select {
+	case <-states:
+	case <-time.After(time.Second):
+		t.Fatal("expected state event")
+	}
+}
+
+func TestCmdLogging(t *testing.T) {
+	// Set up
+	d, c, s, v, err := setup(t, WithTimeouts(Timeouts{Move: 20 * time.Millisecond}))
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: setting up failed: %w", err))
+	}
+	defer func() {
+		d.Close()
+		c.close()
+		s.close()
+		v.close()
+	}()
+
+	// Capture logs
+	l := newTestLogger()
+	d.l = astikit.AdaptStdLogger(l)
+
+	// Start
+	if err = d.Start(); err != nil {
+		t.Fatal(fmt.Errorf("test: starting the drone failed: %w", err))
+	}
+
+	// Successful cmd
+	if err = d.Up(1); err != nil {
+		t.Error(fmt.Errorf("test: sending cmd failed: %w", err))
+	}
+
+	// Timed out cmd
+	c.mt.Lock()
+	c.timeout = true
+	c.mt.Unlock()
+	if err = d.Down(1); err == nil {
+		t.Error("expected error")
+	}
+
+	// Check
+	for _, v := range []struct {
+		e      string
+		prefix string
+	}{
+		{prefix: `debug: astitello: cmd="up 1" priority=false canceller=false elapsed=`, e: ` outcome=ok`},
+		{prefix: `error: astitello: cmd="down 1" priority=false canceller=false elapsed=`, e: ` outcome=timeout error="astitello: no response after 20ms: astitello: timeout"`},
+	} {
+		ls := l.lines(v.prefix)
+		if len(ls) != 1 {
+			t.Errorf("expected 1 line starting with %s, got %+v", v.prefix, l.lines(""))
+			continue
+		}
+		if !strings.HasSuffix(ls[0], v.e) {
+			t.Errorf("expected %s to end with %s", ls[0], v.e)
+		}
+	}
+}
+
+func TestInvalidStates(t *testing.T) {
+	// Set up
+	d, c, s, v, err := setup(t)
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: setting up failed: %w", err))
+	}
+	defer func() {
+		d.Close()
+		c.close()
+		s.close()
+		v.close()
+	}()
+
+	// Capture logs
+	l := newTestLogger()
+	d.l = astikit.AdaptStdLogger(l)
+
+	// Start
+	if err = d.Start(); err != nil {
+		t.Fatal(fmt.Errorf("test: starting the drone failed: %w", err))
+	}
+
+	// Handle states
+	states := make(chan State, 10)
+	d.On(StateEvent, StateEventHandler(func(s State) { states <- s }))
+
+	// Send a valid state, empty datagrams, invalid states and a valid state again
+	for _, b := range []string{strState, "", " \r\n", "invalid", "pitch:a;", "invalid", strState} {
+		if _, err = s.conn.Write([]byte(b)); err != nil {
+			t.Fatal(fmt.Errorf("test: writing state failed: %w", err))
+		}
+	}
+
+	// Wait for valid states
+	for i := 0; i < 2; i++ {
+		select {
+		case <-states:
+		case <-time.After(time.Second):
+			t.Fatalf("expected state %d", i+1)
+		}
+	}
+
+	// Check
+	if e, g := strState, d.RawState(); e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+	if e, g := expectedState, d.State(); e != g {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+	if ls := l.lines("error: "); len(ls) != 1 {
+		t.Errorf("expected 1 error, got %+v", ls)
+	} else if e := "error: astitello: creating state failed: astitello: invalid state field \"invalid\""; ls[0] != e {
+		t.Errorf("expected %s, got %s", e, ls[0])
+	}
+	d.ms.Lock()
+	if e, g := 2, d.stateErrs; e != g {
+		t.Errorf("expected %d skipped errors, got %d", e, g)
+	}
+	d.ms.Unlock()
+}
+
+func TestTimeouts(t *testing.T) {
+	// Defaults
+	ts := Timeouts{Move: time.Millisecond}
+	if e, g := time.Millisecond, ts.move(); e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+	if e, g := takeOffTimeout, ts.takeOff(); e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+	if e, g := defaultTimeout, ts.query(); e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+	if e, g := 2*time.Second, (Timeouts{Default: 2 * time.Second}).query(); e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t, WithTimeouts(Timeouts{Move: 20 * time.Millisecond}))
+	defer teardown()
+
+	// Make the drone stop responding
+	c.mt.Lock()
+	c.timeout = true
+	c.mt.Unlock()
+
+	// Move should time out quickly
+	n := time.Now()
+	if err := d.Forward(1); err == nil {
+		t.Error("expected error")
+	}
+	if g := time.Since(n); g > time.Second {
+		t.Errorf("expected forward to take less than 1s, took %s", g)
+	}
+}
+
+// Reusing read buffers instead of allocating 2048 bytes per datagram:
+// before: 2224 B/op   5 allocs/op
+// after:   176 B/op   4 allocs/op
+func BenchmarkReadVideo(b *testing.B) {
+	// Set up and start
+	d, _, _, v, teardown := setupAndStart(b)
+	defer teardown()
+
+	// Handle video packets
+	ps := make(chan []byte)
+	d.On(VideoPacketEvent, VideoPacketEventHandler(func(p []byte) { ps <- p }))
+
+	// Loop
+	p := bytes.Repeat([]byte("a"), 100)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := v.conn.Write(p); err != nil {
+			b.Fatal(fmt.Errorf("test: writing video packet failed: %w", err))
+		}
+		<-ps
+	}
+}
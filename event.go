@@ -0,0 +1,192 @@
+package astitello
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astikit"
+)
+
+// eventer dispatches events to their handlers sequentially in a dedicated goroutine
+// Unlike astikit.Eventer, a new goroutine is used for every session: events dispatched before a session is
+// stopped are still processed without preventing the next session from starting right away. Handlers
+// can also be removed one by one, and panicking handlers are recovered and reported to onPanic when set. Handlers of the
+// same name run by descending priority, and by registration order for the same priority.
+// When workers is set, handlers are started in that order but run concurrently in up to cap(workers) goroutines
+// so that a slow handler doesn't delay other events, which means handlers may not be done in that order.
+type eventer struct {
+	c       *astikit.Chan
+	hs      map[string][]*eventerHandler
+	m       *sync.Mutex // Locks c and hs
+	onPanic func(name string, v interface{})
+	workers chan struct{}
+}
+
+type eventerHandler struct {
+	h        eventerTimedHandler
+	priority int
+}
+
+// eventerTimedHandler is an handler that also receives the time the event has been dispatched at
+type eventerTimedHandler func(at time.Time, payload interface{})
+
+func newEventer() *eventer {
+	return &eventer{
+		c:  newEventerChan(),
+		hs: make(map[string][]*eventerHandler),
+		m:  &sync.Mutex{},
+	}
+}
+
+func newEventerChan() *astikit.Chan {
+	return astikit.NewChan(astikit.ChanOptions{ProcessAll: true})
+}
+
+// On adds an handler with the default priority for a specific name and returns a function removing it
+func (e *eventer) On(name string, h astikit.EventerHandler) (off func()) {
+	return e.OnPriority(name, 0, h)
+}
+
+// OnPriority adds an handler for a specific name and returns a function removing it. It runs before handlers
+// with a lower priority.
+func (e *eventer) OnPriority(name string, priority int, h astikit.EventerHandler) (off func()) {
+	return e.onPriorityTimed(name, priority, func(_ time.Time, payload interface{}) { h(payload) })
+}
+
+// onTimed adds a timed handler with the default priority for a specific name and returns a function removing it
+func (e *eventer) onTimed(name string, h eventerTimedHandler) (off func()) {
+	return e.onPriorityTimed(name, 0, h)
+}
+
+func (e *eventer) onPriorityTimed(name string, priority int, h eventerTimedHandler) (off func()) {
+	// Lock
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	// Get position, after handlers with a higher or equal priority
+	hs := e.hs[name]
+	idx := len(hs)
+	for i, v := range hs {
+		if v.priority < priority {
+			idx = i
+			break
+		}
+	}
+
+	// Add handler
+	eh := &eventerHandler{
+		h:        h,
+		priority: priority,
+	}
+	e.hs[name] = append(hs[:idx:idx], append([]*eventerHandler{eh}, hs[idx:]...)...)
+	return func() {
+		// Lock
+		e.m.Lock()
+		defer e.m.Unlock()
+
+		// Remove handler
+		var hs []*eventerHandler
+		for _, v := range e.hs[name] {
+			if v != eh {
+				hs = append(hs, v)
+			}
+		}
+		e.hs[name] = hs
+	}
+}
+
+// Dispatch dispatches a payload for a specific name
+func (e *eventer) Dispatch(name string, payload interface{}) {
+	// Lock
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	// Get time while locked so that it's consistent with the order events are processed in
+	at := time.Now()
+
+	// Loop through handlers
+	for _, h := range e.hs[name] {
+		func(h eventerTimedHandler) {
+			// Add to chan
+			e.c.Add(func() {
+				// No workers
+				if e.workers == nil {
+					e.handle(name, h, at, payload)
+					return
+				}
+
+				// Wait for a worker to be available
+				e.workers <- struct{}{}
+				go func() {
+					defer func() { <-e.workers }()
+					e.handle(name, h, at, payload)
+				}()
+			})
+		}(h.h)
+	}
+}
+
+func (e *eventer) handle(name string, h eventerTimedHandler, at time.Time, payload interface{}) {
+	// A panicking handler must not prevent other events from being processed
+	defer e.handlePanic(name)
+	h(at, payload)
+}
+
+func (e *eventer) handlePanic(name string) {
+	if v := recover(); v != nil && e.onPanic != nil {
+		e.onPanic(name, v)
+	}
+}
+
+// processed returns a chan that is closed once the events that have already been dispatched are processed
+func (e *eventer) processed() <-chan struct{} {
+	// Lock
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	// Add to chan
+	c := make(chan struct{})
+	e.c.Add(func() {
+		// Wait for handlers running in workers to be done
+		for idx := 0; idx < cap(e.workers); idx++ {
+			e.workers <- struct{}{}
+		}
+		for idx := 0; idx < cap(e.workers); idx++ {
+			<-e.workers
+		}
+		close(c)
+	})
+	return c
+}
+
+// Start starts processing events in a new goroutine until the context is done
+func (e *eventer) Start(ctx context.Context) {
+	// Get chan
+	e.m.Lock()
+	c := e.c
+	e.m.Unlock()
+
+	// Start chan
+	go c.Start(ctx)
+}
+
+// Reset removes all handlers
+func (e *eventer) Reset() {
+	e.m.Lock()
+	defer e.m.Unlock()
+	e.hs = make(map[string][]*eventerHandler)
+}
+
+// Stop stops the current session. Events that have already been dispatched are still processed.
+func (e *eventer) Stop() {
+	// Lock
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	// Stop chan
+	e.c.Stop()
+
+	// Create new chan for the next session
+	e.c = newEventerChan()
+}
@@ -69,7 +69,18 @@ func main() {
 		video = true
 	} else {
 		// Log
-		l.Println("main: ffmpeg was not found, video won't be started")
+		l.Println("main: ffmpeg was not found, raw video will be recorded to example.h264")
+
+		// Record video
+		w.NewTask().Do(func() {
+			if err := d.RecordVideo(w.Context(), "example.h264"); err != nil {
+				l.Println(fmt.Errorf("main: recording video failed: %w", err))
+				return
+			}
+		})
+
+		// Update
+		video = true
 	}
 
 	// Handle take off event
@@ -105,7 +116,7 @@ func main() {
 		}
 
 		// Log state
-		l.Printf("main: state is: %+v\n", d.State())
+		l.Printf("main: state is: %s\n", d.State())
 
 		// Land
 		if err := d.Land(); err != nil {
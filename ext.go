@@ -0,0 +1,191 @@
+package astitello
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EXT cmds are forwarded to the open-source controller of the RoboMaster Tello Talent, which drives its LED,
+// its matrix display and its TOF sensor. Other drones respond with an error.
+
+// extRespHandler accepts "ok" as well as the "<module> ok" responses sent by the open-source controller
+func extRespHandler(resp string) (err error) {
+	// Check response
+	if resp != "ok" && !strings.HasSuffix(resp, " ok") {
+		err = fmt.Errorf("astitello: invalid response: %w", &DroneError{Raw: resp})
+		return
+	}
+	return
+}
+
+// SendExt sends an EXT cmd made of the provided arguments, e.g. SendExt("led", "255", "0", "0")
+// It only works on the Tello Talent.
+func (d *Drone) SendExt(args ...string) (err error) {
+	// Validate
+	if len(args) == 0 {
+		err = fmt.Errorf("astitello: no EXT arguments: %w", ErrInvalidArgument)
+		return
+	}
+
+	// Check capabilities
+	if err = d.checkExt(); err != nil {
+		return
+	}
+
+	// Send cmd
+	if err = d.sendCmd(&cmd{
+		cmd:     "EXT " + strings.Join(args, " "),
+		h:       extRespHandler,
+		timeout: d.o.timeouts.fallback(),
+	}); err != nil {
+		err = fmt.Errorf("astitello: sending EXT cmd failed: %w", err)
+		return
+	}
+	return
+}
+
+// SetLED sets the Tello Talent's top LED to a solid color
+func (d *Drone) SetLED(r, g, b uint8) error {
+	return d.SendExt("led", formatUint8(r), formatUint8(g), formatUint8(b))
+}
+
+// SetLEDBreath makes the Tello Talent's top LED breathe with a color at freq Hz
+// freq must be between 0.1 and 2.5.
+func (d *Drone) SetLEDBreath(freq float64, r, g, b uint8) (err error) {
+	// Validate
+	if freq < 0.1 || freq > 2.5 {
+		err = fmt.Errorf("astitello: frequency %s is not between 0.1 and 2.5: %w", formatFrequency(freq), ErrInvalidArgument)
+		return
+	}
+	return d.SendExt("led", "br", formatFrequency(freq), formatUint8(r), formatUint8(g), formatUint8(b))
+}
+
+// SetLEDBlink makes the Tello Talent's top LED alternate between two colors at freq Hz
+// freq must be between 0.1 and 10.
+func (d *Drone) SetLEDBlink(freq float64, r1, g1, b1, r2, g2, b2 uint8) (err error) {
+	// Validate
+	if freq < 0.1 || freq > 10 {
+		err = fmt.Errorf("astitello: frequency %s is not between 0.1 and 10: %w", formatFrequency(freq), ErrInvalidArgument)
+		return
+	}
+	return d.SendExt("led", "bl", formatFrequency(freq), formatUint8(r1), formatUint8(g1), formatUint8(b1), formatUint8(r2), formatUint8(g2), formatUint8(b2))
+}
+
+// ExtTOF returns the distance measured by the Tello Talent's external TOF sensor (mm)
+// This is not the onboard ToF sensor used in the state. The sensor returns 8192 when out of range.
+func (d *Drone) ExtTOF() (x int, err error) {
+	// Check capabilities
+	if err = d.checkExt(); err != nil {
+		return
+	}
+	return d.extTOF()
+}
+
+// extTOF doesn't check capabilities so that it can be used to probe them
+func (d *Drone) extTOF() (x int, err error) {
+	// Send cmd
+	// It returns "tof 1234"
+	if err = d.sendCmd(&cmd{
+		cmd: "EXT tof?",
+		h: func(resp string) (err error) {
+			x, err = parseExtTOF(resp)
+			return
+		},
+		timeout: d.o.timeouts.query(),
+	}); err != nil {
+		err = fmt.Errorf("astitello: sending EXT tof? cmd failed: %w", err)
+		return
+	}
+	return
+}
+
+func parseExtTOF(resp string) (x int, err error) {
+	// Drones other than the Tello Talent don't respond with a distance
+	if !strings.HasPrefix(resp, "tof ") {
+		err = fmt.Errorf("astitello: invalid response: %w", &DroneError{Raw: resp})
+		return
+	}
+	return parseInt(strings.TrimPrefix(resp, "tof "))
+}
+
+func (d *Drone) checkExt() error {
+	return d.checkCapability("EXT", func(c Capabilities) bool { return c.Ext }, "requires the Tello Talent")
+}
+
+func formatFrequency(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func formatUint8(i uint8) string {
+	return strconv.Itoa(int(i))
+}
+
+// Matrix colors
+const (
+	MatrixColorBlue   = 'b'
+	MatrixColorOff    = '0'
+	MatrixColorPurple = 'p'
+	MatrixColorRed    = 'r'
+)
+
+// Matrix scroll directions
+const (
+	MatrixDirectionDown  = 'd'
+	MatrixDirectionLeft  = 'l'
+	MatrixDirectionRight = 'r'
+	MatrixDirectionUp    = 'u'
+)
+
+// DisplayMatrix displays a pattern on the Tello Talent's 8x8 matrix display
+// Rows are displayed from top to bottom and each cell is a MatrixColor... constant.
+func (d *Drone) DisplayMatrix(pattern [8][8]rune) (err error) {
+	// Loop through rows
+	var b strings.Builder
+	for i, row := range pattern {
+		for j, c := range row {
+			// Validate
+			if !validMatrixColor(c, true) {
+				err = fmt.Errorf("astitello: color %q of cell %d:%d is invalid: %w", c, i, j, ErrInvalidArgument)
+				return
+			}
+
+			// Append
+			b.WriteRune(c)
+		}
+	}
+	return d.SendExt("mled", "g", b.String())
+}
+
+// DisplayScrollText scrolls text on the Tello Talent's matrix display
+// color is a MatrixColor... constant other than MatrixColorOff, direction is a MatrixDirection... constant, rate is
+// the scrolling frequency in Hz and must be between 0.1 and 2.5, and text must be between 1 and 70 characters.
+func (d *Drone) DisplayScrollText(text string, color rune, direction rune, rate float64) (err error) {
+	// Validate
+	switch {
+	case len(text) == 0 || len(text) > 70:
+		err = fmt.Errorf("astitello: text length %d is not between 1 and 70: %w", len(text), ErrInvalidArgument)
+	case strings.ContainsAny(text, " \t\r\n"):
+		err = fmt.Errorf("astitello: text %q contains whitespaces: %w", text, ErrInvalidArgument)
+	case !validMatrixColor(color, false):
+		err = fmt.Errorf("astitello: color %q is invalid: %w", color, ErrInvalidArgument)
+	case direction != MatrixDirectionDown && direction != MatrixDirectionLeft && direction != MatrixDirectionRight && direction != MatrixDirectionUp:
+		err = fmt.Errorf("astitello: direction %q is invalid: %w", direction, ErrInvalidArgument)
+	case rate < 0.1 || rate > 2.5:
+		err = fmt.Errorf("astitello: rate %s is not between 0.1 and 2.5: %w", formatFrequency(rate), ErrInvalidArgument)
+	}
+	if err != nil {
+		return
+	}
+	return d.SendExt("mled", string(direction), string(color), formatFrequency(rate), text)
+}
+
+func validMatrixColor(c rune, off bool) bool {
+	switch c {
+	case MatrixColorBlue, MatrixColorPurple, MatrixColorRed:
+		return true
+	case MatrixColorOff:
+		return off
+	}
+	return false
+}
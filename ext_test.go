@@ -0,0 +1,167 @@
+package astitello
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExt(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Capabilities are unknown, therefore EXT cmds are attempted
+	d.resetCapabilities()
+
+	// Respond like the Tello Talent
+	c.mt.Lock()
+	h := c.h
+	c.h = func(cmd []byte) []byte {
+		if strings.HasPrefix(string(cmd), "EXT led") {
+			return []byte("led ok")
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+
+	// Send cmds
+	for _, f := range []func() error{
+		func() error { return d.SendExt("led", "1", "2", "3") },
+		func() error { return d.SetLED(255, 0, 128) },
+		func() error { return d.SetLEDBreath(0.5, 0, 255, 0) },
+		func() error { return d.SetLEDBlink(10, 255, 0, 0, 0, 0, 255) },
+	} {
+		if err := f(); err != nil {
+			t.Error(fmt.Errorf("test: sending cmd failed: %w", err))
+		}
+	}
+	if e, g := []string{
+		"command",
+		"sdk?",
+		"sn?",
+		"EXT led 1 2 3",
+		"EXT led 255 0 128",
+		"EXT led br 0.5 0 255 0",
+		"EXT led bl 10 255 0 0 0 0 255",
+	}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+
+	// Invalid arguments
+	for _, f := range []func() error{
+		func() error { return d.SendExt() },
+		func() error { return d.SetLEDBreath(0.05, 0, 0, 0) },
+		func() error { return d.SetLEDBreath(2.6, 0, 0, 0) },
+		func() error { return d.SetLEDBlink(11, 0, 0, 0, 0, 0, 0) },
+	} {
+		if err := f(); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("expected %s, got %v", ErrInvalidArgument, err)
+		}
+	}
+
+	// Not a Tello Talent
+	c.mt.Lock()
+	c.h = func([]byte) []byte { return []byte("error") }
+	c.mt.Unlock()
+	var de *DroneError
+	if err := d.SetLED(0, 0, 0); !errors.As(err, &de) {
+		t.Errorf("expected DroneError, got %v", err)
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Capabilities are unknown, therefore EXT cmds are attempted
+	d.resetCapabilities()
+
+	// Respond like the Tello Talent
+	c.mt.Lock()
+	h := c.h
+	c.h = func(cmd []byte) []byte {
+		if strings.HasPrefix(string(cmd), "EXT mled") {
+			return []byte("matrix ok")
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+
+	// Heart
+	var p [8][8]rune
+	for i, r := range []string{
+		"00000000",
+		"0rr00rr0",
+		"rrrrrrrr",
+		"rrrrrrrr",
+		"0rrrrrr0",
+		"00rrrr00",
+		"000rr000",
+		"0000000b",
+	} {
+		copy(p[i][:], []rune(r))
+	}
+	if err := d.DisplayMatrix(p); err != nil {
+		t.Error(fmt.Errorf("test: displaying matrix failed: %w", err))
+	}
+	if err := d.DisplayScrollText("Hi!", MatrixColorPurple, MatrixDirectionLeft, 1.5); err != nil {
+		t.Error(fmt.Errorf("test: displaying scroll text failed: %w", err))
+	}
+	if e, g := []string{
+		"command",
+		"sdk?",
+		"sn?",
+		"EXT mled g 000000000rr00rr0rrrrrrrrrrrrrrrr0rrrrrr000rrrr00000rr0000000000b",
+		"EXT mled l p 1.5 Hi!",
+	}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+
+	// Invalid arguments
+	p[3][4] = 'x'
+	for _, f := range []func() error{
+		func() error { return d.DisplayMatrix(p) },
+		func() error { return d.DisplayScrollText("", MatrixColorRed, MatrixDirectionLeft, 1) },
+		func() error {
+			return d.DisplayScrollText(strings.Repeat("a", 71), MatrixColorRed, MatrixDirectionLeft, 1)
+		},
+		func() error { return d.DisplayScrollText("a b", MatrixColorRed, MatrixDirectionLeft, 1) },
+		func() error { return d.DisplayScrollText("a", MatrixColorOff, MatrixDirectionLeft, 1) },
+		func() error { return d.DisplayScrollText("a", MatrixColorRed, 'x', 1) },
+		func() error { return d.DisplayScrollText("a", MatrixColorRed, MatrixDirectionLeft, 3) },
+	} {
+		if err := f(); !errors.Is(err, ErrInvalidArgument) {
+			t.Errorf("expected %s, got %v", ErrInvalidArgument, err)
+		}
+	}
+}
+
+func TestExtTOF(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Capabilities are unknown, therefore EXT cmds are attempted
+	d.resetCapabilities()
+
+	// Query
+	x, err := d.ExtTOF()
+	if err != nil {
+		t.Error(fmt.Errorf("test: querying EXT tof failed: %w", err))
+	} else if e := 1234; x != e {
+		t.Errorf("expected %d, got %d", e, x)
+	}
+
+	// Not a Tello Talent
+	c.mt.Lock()
+	c.h = func([]byte) []byte { return []byte("unknown command: EXT") }
+	c.mt.Unlock()
+	var de *DroneError
+	if _, err = d.ExtTOF(); !errors.As(err, &de) {
+		t.Errorf("expected DroneError, got %v", err)
+	}
+}
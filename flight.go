@@ -0,0 +1,110 @@
+package astitello
+
+import "fmt"
+
+// Flight states
+const (
+	FlightStateAirborne  FlightState = "airborne"
+	FlightStateGrounded  FlightState = "grounded"
+	FlightStateLanding   FlightState = "landing"
+	FlightStateTakingOff FlightState = "taking.off"
+	FlightStateUnknown   FlightState = "unknown"
+)
+
+// FlightState represents the flight state of the drone, which is updated by take off and land cmds as well as
+// by the height reported in the state (see WithFlightDetection). It is unknown until one of those happens
+// after connecting since the drone may already be flying.
+type FlightState string
+
+// FlightState returns the flight state of the drone
+func (d *Drone) FlightState() FlightState {
+	d.ms.Lock()
+	defer d.ms.Unlock()
+	return d.fs
+}
+
+// setFlightState returns the previous flight state
+func (d *Drone) setFlightState(fs FlightState) (previous FlightState) {
+	d.ms.Lock()
+	defer d.ms.Unlock()
+	previous = d.fs
+	d.fs = fs
+	return
+}
+
+// startLanding switches the flight state to landing unless the drone is on the ground, in which case ok is false
+func (d *Drone) startLanding() (previous FlightState, ok bool) {
+	d.ms.Lock()
+	defer d.ms.Unlock()
+	if previous = d.fs; previous == FlightStateGrounded {
+		return
+	}
+	d.fs = FlightStateLanding
+	ok = true
+	return
+}
+
+// checkAirborne makes sure movement cmds are not sent while the drone is on the ground or landing, since it
+// would respond with an error anyway. Movement cmds are allowed while taking off since they are sent once the
+// take off is over.
+func (d *Drone) checkAirborne() (err error) {
+	switch fs := d.FlightState(); fs {
+	case FlightStateGrounded, FlightStateLanding:
+		err = fmt.Errorf("astitello: flight state is %s: %w", fs, ErrNotAirborne)
+	}
+	return
+}
+
+// flightDetector detects take offs and landings from the height reported in the state, regardless of
+// which cmd triggered them or whether a cmd triggered them at all
+type flightDetector struct {
+	airborne  bool
+	count     int
+	debounce  int
+	stable    int // Number of consecutive states consistent with airborne
+	threshold int
+}
+
+func newFlightDetector(o options) *flightDetector {
+	return &flightDetector{
+		debounce:  o.flightDetectionDebounce,
+		threshold: o.flightDetectionThreshold,
+	}
+}
+
+// update returns the name of the event that should be dispatched, if any
+func (fd *flightDetector) update(s State) (name string) {
+	// Detection is disabled
+	if fd.threshold <= 0 {
+		return
+	}
+
+	// Nothing has changed
+	airborne := s.Height >= fd.threshold
+	if airborne == fd.airborne {
+		fd.count = 0
+		if fd.stable < fd.debounce {
+			fd.stable++
+		}
+		return
+	}
+	fd.stable = 0
+
+	// Make sure the change is consistent
+	if fd.count++; fd.count < fd.debounce {
+		return
+	}
+
+	// Update
+	fd.airborne = airborne
+	fd.count = 0
+	if airborne {
+		return AirborneEvent
+	}
+	return GroundedEvent
+}
+
+// grounded returns whether enough consecutive states reported the drone on the ground
+func (fd *flightDetector) grounded() bool {
+	return fd.threshold > 0 && !fd.airborne && fd.stable >= fd.debounce
+}
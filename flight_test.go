@@ -0,0 +1,143 @@
+package astitello
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFlightDetector(t *testing.T) {
+	fd := newFlightDetector(options{flightDetectionDebounce: 2, flightDetectionThreshold: 10})
+	var g []string
+	for _, h := range []int{0, 12, 0, 12, 15, 20, 5, 20, 5, 3, 0} {
+		if name := fd.update(State{Height: h}); name != "" {
+			g = append(g, name)
+		}
+	}
+	if e := []string{AirborneEvent, GroundedEvent}; !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+
+	// Disabled
+	fd = newFlightDetector(options{})
+	if name := fd.update(State{Height: 100}); name != "" {
+		t.Errorf("expected no event, got %s", name)
+	}
+}
+
+func TestFlightDetection(t *testing.T) {
+	// Set up and start
+	d, _, s, _, teardown := setupAndStart(t, WithFlightDetection(10, 2))
+	defer teardown()
+
+	// Handle events
+	m := &sync.Mutex{}
+	var g []string
+	for _, name := range []string{AirborneEvent, GroundedEvent} {
+		name := name
+		d.On(name, func(interface{}) {
+			m.Lock()
+			defer m.Unlock()
+			g = append(g, name)
+		})
+	}
+
+	// Write synthetic states
+	for _, h := range []int{0, 17, 17, 17, 0, 0} {
+		if _, err := s.conn.Write([]byte(fmt.Sprintf("pitch:0;roll:0;yaw:0;vgx:0;vgy:0;vgz:0;templ:0;temph:0;tof:0;h:%d;bat:0;baro:0.0;time:0;agx:0.0;agy:0.0;agz:0.0;", h))); err != nil {
+			t.Fatal(fmt.Errorf("test: writing state failed: %w", err))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Check
+	time.Sleep(20 * time.Millisecond)
+	m.Lock()
+	defer m.Unlock()
+	if e := []string{AirborneEvent, GroundedEvent}; !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+}
+
+func TestFlightState(t *testing.T) {
+	// Set up and start
+	d, c, s, _, teardown := setupAndStart(t, WithFlightDetection(10, 2))
+	defer teardown()
+
+	// Flight state is unknown after connecting
+	if e, g := FlightStateUnknown, d.FlightState(); e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+
+	// Write states reporting the drone on the ground
+	for i := 0; i < 3; i++ {
+		if _, err := s.conn.Write([]byte("pitch:0;roll:0;yaw:0;vgx:0;vgy:0;vgz:0;templ:0;temph:0;tof:0;h:0;bat:0;baro:0.0;time:0;agx:0.0;agy:0.0;agz:0.0;")); err != nil {
+			t.Fatal(fmt.Errorf("test: writing state failed: %w", err))
+		}
+	}
+	for n := time.Now(); d.FlightState() != FlightStateGrounded; time.Sleep(time.Millisecond) {
+		if time.Since(n) > time.Second {
+			t.Fatalf("expected %s, got %s", FlightStateGrounded, d.FlightState())
+		}
+	}
+
+	// Move before take off
+	if err := d.Up(1); !errors.Is(err, ErrNotAirborne) {
+		t.Errorf("expected %s, got %v", ErrNotAirborne, err)
+	}
+
+	// Take off, move and land
+	if err := d.TakeOff(); err != nil {
+		t.Error(fmt.Errorf("test: taking off failed: %w", err))
+	}
+	if e, g := FlightStateAirborne, d.FlightState(); e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+	if err := d.Up(1); err != nil {
+		t.Error(fmt.Errorf("test: moving up failed: %w", err))
+	}
+	if err := d.Land(); err != nil {
+		t.Error(fmt.Errorf("test: landing failed: %w", err))
+	}
+	if e, g := FlightStateGrounded, d.FlightState(); e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+
+	// Move after landing
+	if err := d.Go(100, 0, 0, 10); !errors.Is(err, ErrNotAirborne) {
+		t.Errorf("expected %s, got %v", ErrNotAirborne, err)
+	}
+	if e, g := []string{"command", "sdk?", "sn?", "takeoff", "up 1", "land"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+}
+
+func TestLandIdempotent(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Land twice
+	for i := 0; i < 2; i++ {
+		if err := d.Land(); err != nil {
+			t.Error(fmt.Errorf("test: landing failed: %w", err))
+		}
+	}
+	if e, g := []string{"command", "sdk?", "sn?", "land"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+
+	// Landing is sent again once airborne
+	if err := d.TakeOff(); err != nil {
+		t.Error(fmt.Errorf("test: taking off failed: %w", err))
+	}
+	if err := d.Land(); err != nil {
+		t.Error(fmt.Errorf("test: landing failed: %w", err))
+	}
+	if e, g := []string{"command", "sdk?", "sn?", "land", "takeoff", "land"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+}
@@ -0,0 +1,74 @@
+package astitello
+
+import (
+	"context"
+	"time"
+)
+
+// Size of the channel returned by FlightLog
+var flightLogBufferSize = 100
+
+// Flight log entry kinds
+const (
+	FlightLogKindCommand    FlightLogKind = "command"
+	FlightLogKindError      FlightLogKind = "error"
+	FlightLogKindState      FlightLogKind = "state"
+	FlightLogKindVideoStats FlightLogKind = "video.stats"
+)
+
+// FlightLogKind represents the kind of a flight log entry
+type FlightLogKind string
+
+// FlightLogEntry represents an entry of the flight log
+// Only the fields matching Kind are set.
+type FlightLogEntry struct {
+	At         time.Time      `json:"at"` // When the event has been dispatched
+	Command    *CommandResult `json:"command,omitempty"`
+	Err        error          `json:"-"`
+	Error      string         `json:"error,omitempty"` // The error's message, which is what is serialized
+	Kind       FlightLogKind  `json:"kind"`
+	State      *State         `json:"state,omitempty"`
+	VideoStats *VideoStats    `json:"video_stats,omitempty"`
+}
+
+// FlightLog returns a channel merging cmds, states, video stats and errors into a single stream of entries,
+// which is convenient to build a black-box recorder or a replay UI. The channel is closed once the context
+// is done.
+// Entries are sent in the order the events have been dispatched and their At field is set when they are
+// dispatched, which makes it non-decreasing. When WithEventWorkers is used, handlers run concurrently and
+// entries may be sent out of order, sort them by At if needed. Entries are dropped if the channel is full.
+func (d *Drone) FlightLog(ctx context.Context) <-chan FlightLogEntry {
+	// Create channel
+	c := make(chan FlightLogEntry, flightLogBufferSize)
+	send := sendUntil(ctx, func() { close(c) })
+
+	// Create send func
+	sendEntry := func(e FlightLogEntry) {
+		send(func() {
+			// Send without blocking
+			select {
+			case c <- e:
+			default:
+			}
+		})
+	}
+
+	// Handle events
+	d.onUntilTimed(ctx, CommandEvent, func(at time.Time, payload interface{}) {
+		r := payload.(CommandResult)
+		sendEntry(FlightLogEntry{At: at, Command: &r, Kind: FlightLogKindCommand})
+	})
+	d.onUntilTimed(ctx, ErrorEvent, func(at time.Time, payload interface{}) {
+		err := payload.(error)
+		sendEntry(FlightLogEntry{At: at, Err: err, Error: err.Error(), Kind: FlightLogKindError})
+	})
+	d.onUntilTimed(ctx, StateEvent, func(at time.Time, payload interface{}) {
+		s := payload.(State)
+		sendEntry(FlightLogEntry{At: at, Kind: FlightLogKindState, State: &s})
+	})
+	d.onUntilTimed(ctx, VideoStatsEvent, func(at time.Time, payload interface{}) {
+		s := payload.(VideoStats)
+		sendEntry(FlightLogEntry{At: at, Kind: FlightLogKindVideoStats, VideoStats: &s})
+	})
+	return c
+}
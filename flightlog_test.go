@@ -0,0 +1,94 @@
+package astitello
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestFlightLog(t *testing.T) {
+	// Set up and start
+	d, _, s, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Get flight log
+	ctx, cancel := context.WithCancel(context.Background())
+	es := d.FlightLog(ctx)
+
+	// Get next entry
+	next := func(k FlightLogKind) (e FlightLogEntry) {
+		select {
+		case e = <-es:
+			if e.Kind != k {
+				t.Fatalf("expected %s, got %+v", k, e)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected %s", k)
+		}
+		return
+	}
+
+	// Cmd
+	if err := d.Up(1); err != nil {
+		t.Fatal(fmt.Errorf("test: sending cmd failed: %w", err))
+	}
+	var g []FlightLogEntry
+	e := next(FlightLogKindCommand)
+	if e.Command.Command != "up 1" || e.Command.Err != nil {
+		t.Errorf("invalid command %+v", *e.Command)
+	}
+	g = append(g, e)
+
+	// State
+	if _, err := s.conn.Write([]byte(strState)); err != nil {
+		t.Fatal(fmt.Errorf("test: writing state failed: %w", err))
+	}
+	e = next(FlightLogKindState)
+	if *e.State != expectedState {
+		t.Errorf("expected %+v, got %+v", expectedState, *e.State)
+	}
+	g = append(g, e)
+
+	// Error and video stats
+	n1 := time.Now()
+	d.e.Dispatch(ErrorEvent, errors.New("test"))
+	n2 := time.Now()
+	d.e.Dispatch(VideoStatsEvent, VideoStats{Packets: 1})
+	g = append(g, next(FlightLogKindError), next(FlightLogKindVideoStats))
+
+	// Time is when the event has been dispatched
+	if at := g[2].At; at.Before(n1) || at.After(n2) {
+		t.Errorf("expected time between %s and %s, got %s", n1, n2, at)
+	}
+
+	// Errors are serialized
+	b, err := json.Marshal(g[2])
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: marshaling failed: %w", err))
+	}
+	if e := `"error":"test"`; !bytes.Contains(b, []byte(e)) {
+		t.Errorf("expected %s to contain %s", b, e)
+	}
+
+	// Entries should be time-ordered
+	for idx := 1; idx < len(g); idx++ {
+		if g[idx].At.Before(g[idx-1].At) {
+			t.Errorf("entry %d is before entry %d", idx, idx-1)
+		}
+	}
+
+	// Channel should be closed once the context is done
+	cancel()
+	for closed := false; !closed; {
+		select {
+		case _, ok := <-es:
+			closed = !ok
+		case <-time.After(time.Second):
+			t.Fatal("expected channel to be closed")
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package astitello
+
+import "math"
+
+// GamepadAxes represents raw gamepad axes values, between -1.0 and 1.0
+type GamepadAxes struct {
+	ForwardBackward float64
+	LeftRight       float64
+	UpDown          float64
+	Yaw             float64
+}
+
+// Gamepad represents an object capable of providing raw gamepad axes values
+type Gamepad interface {
+	Axes() GamepadAxes
+}
+
+// GamepadMapping represents the way raw gamepad axes values are mapped to sticks positions
+type GamepadMapping struct {
+	// Raw values whose absolute value is lower than or equal to the deadzone are considered neutral.
+	// Values are rescaled so that sticks positions start right after the deadzone.
+	Deadzone float64
+	// Expo curve, between 0 (linear) and 1 (cubic). The higher, the more precise around the center.
+	Expo float64
+}
+
+// DefaultGamepadMapping is the mapping used by MapGamepad
+var DefaultGamepadMapping = GamepadMapping{Deadzone: 0.05}
+
+// MapGamepad maps raw gamepad axes values to sticks positions using the default mapping
+func MapGamepad(axes GamepadAxes) (lr, fb, ud, y int) {
+	return DefaultGamepadMapping.Map(axes)
+}
+
+// Map maps raw gamepad axes values to sticks positions that can be provided to SetSticks
+func (m GamepadMapping) Map(axes GamepadAxes) (lr, fb, ud, y int) {
+	return m.mapValue(axes.LeftRight), m.mapValue(axes.ForwardBackward), m.mapValue(axes.UpDown), m.mapValue(axes.Yaw)
+}
+
+func (m GamepadMapping) mapValue(v float64) int {
+	// Clamp
+	v = math.Max(-1, math.Min(1, v))
+
+	// Deadzone
+	a := math.Abs(v)
+	if a <= m.Deadzone {
+		return 0
+	}
+	a = (a - m.Deadzone) / (1 - m.Deadzone)
+
+	// Expo
+	a = (1-m.Expo)*a + m.Expo*a*a*a
+	return int(math.Copysign(math.Round(a*100), v))
+}
@@ -0,0 +1,32 @@
+package astitello
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMapGamepad(t *testing.T) {
+	for _, v := range []struct {
+		a GamepadAxes
+		e []int
+		m GamepadMapping
+	}{
+		// Clamping
+		{a: GamepadAxes{LeftRight: 2, ForwardBackward: -3, UpDown: 1, Yaw: -1}, e: []int{100, -100, 100, -100}},
+		// Deadzone edges
+		{a: GamepadAxes{LeftRight: 0.1, ForwardBackward: -0.1, UpDown: 0.1001, Yaw: 0.55}, e: []int{0, 0, 0, 50}, m: GamepadMapping{Deadzone: 0.1}},
+		// Expo
+		{a: GamepadAxes{LeftRight: 0.5, ForwardBackward: -0.5, UpDown: 1}, e: []int{13, -13, 100, 0}, m: GamepadMapping{Expo: 1}},
+	} {
+		lr, fb, ud, y := v.m.Map(v.a)
+		if g := []int{lr, fb, ud, y}; !reflect.DeepEqual(g, v.e) {
+			t.Errorf("expected %+v, got %+v", v.e, g)
+		}
+	}
+
+	// Default mapping
+	lr, fb, ud, y := MapGamepad(GamepadAxes{LeftRight: 0.05, ForwardBackward: 1})
+	if e, g := []int{0, 100, 0, 0}, []int{lr, fb, ud, y}; !reflect.DeepEqual(g, e) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+}
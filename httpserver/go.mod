@@ -0,0 +1,12 @@
+module github.com/asticode/go-astitello/httpserver
+
+go 1.13
+
+require (
+	github.com/asticode/go-astikit v0.2.0
+	github.com/asticode/go-astitello v0.1.0
+	github.com/gorilla/websocket v1.5.0
+)
+
+// Only used when developing locally, it is ignored by modules depending on this one
+replace github.com/asticode/go-astitello => ../
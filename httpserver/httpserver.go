@@ -0,0 +1,190 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/go-astitello"
+	"github.com/gorilla/websocket"
+)
+
+// Drone represents the drone methods the server relies on. *astitello.Drone implements it.
+type Drone interface {
+	Back(x int) error
+	Down(x int) error
+	Forward(x int) error
+	Land() error
+	Left(x int) error
+	On(name string, h astikit.EventerHandler)
+	Right(x int) error
+	RotateClockwise(x int) error
+	RotateCounterClockwise(x int) error
+	State() astitello.State
+	TakeOff() error
+	Up(x int) error
+}
+
+// Move directions
+const (
+	DirectionBack             = "back"
+	DirectionClockwise        = "cw"
+	DirectionCounterClockwise = "ccw"
+	DirectionDown             = "down"
+	DirectionForward          = "forward"
+	DirectionLeft             = "left"
+	DirectionRight            = "right"
+	DirectionUp               = "up"
+)
+
+// MoveRequest represents the body of a move request
+type MoveRequest struct {
+	Direction string `json:"direction"`
+	// In cm for translations and in degrees for rotations
+	Value int `json:"value"`
+}
+
+// ErrorResponse represents the body of a failed request
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Server represents an HTTP handler exposing the drone's controls as a REST API and streaming its
+// state over a websocket
+type Server struct {
+	d  Drone
+	m  *http.ServeMux
+	mw *sync.Mutex // Locks ws
+	u  websocket.Upgrader
+	ws map[*websocketClient]bool
+}
+
+// New creates a new server
+// Event handlers are removed when the drone is closed, therefore a new server is needed once it's restarted.
+func New(d Drone) (s *Server) {
+	// Create server
+	s = &Server{
+		d:  d,
+		m:  http.NewServeMux(),
+		mw: &sync.Mutex{},
+		ws: make(map[*websocketClient]bool),
+	}
+
+	// Add routes
+	s.m.HandleFunc("/land", s.handleCmd(http.MethodPost, d.Land))
+	s.m.HandleFunc("/move", s.handleMove)
+	s.m.HandleFunc("/state", s.handleState)
+	s.m.HandleFunc("/takeoff", s.handleCmd(http.MethodPost, d.TakeOff))
+	s.m.HandleFunc("/ws", s.handleWebsocket)
+
+	// Handle state
+	d.On(astitello.StateEvent, astitello.StateEventHandler(func(st astitello.State) { s.broadcast(st) }))
+	return
+}
+
+// ServeHTTP implements the http.Handler interface
+func (s *Server) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	s.m.ServeHTTP(rw, r)
+}
+
+func (s *Server) handleCmd(method string, fn func() error) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		// Check method
+		if !checkMethod(rw, r, method) {
+			return
+		}
+
+		// Execute
+		if err := fn(); err != nil {
+			writeError(rw, err)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *Server) handleMove(rw http.ResponseWriter, r *http.Request) {
+	// Check method
+	if !checkMethod(rw, r, http.MethodPost) {
+		return
+	}
+
+	// Unmarshal
+	var b MoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+		writeJSON(rw, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("httpserver: unmarshaling body failed: %s", err)})
+		return
+	}
+
+	// Get fn
+	var fn func(x int) error
+	switch b.Direction {
+	case DirectionBack:
+		fn = s.d.Back
+	case DirectionClockwise:
+		fn = s.d.RotateClockwise
+	case DirectionCounterClockwise:
+		fn = s.d.RotateCounterClockwise
+	case DirectionDown:
+		fn = s.d.Down
+	case DirectionForward:
+		fn = s.d.Forward
+	case DirectionLeft:
+		fn = s.d.Left
+	case DirectionRight:
+		fn = s.d.Right
+	case DirectionUp:
+		fn = s.d.Up
+	default:
+		writeJSON(rw, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("httpserver: unknown direction %s", b.Direction)})
+		return
+	}
+
+	// Move
+	if err := fn(b.Value); err != nil {
+		writeError(rw, err)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleState(rw http.ResponseWriter, r *http.Request) {
+	// Check method
+	if !checkMethod(rw, r, http.MethodGet) {
+		return
+	}
+
+	// Write
+	writeJSON(rw, http.StatusOK, s.d.State())
+}
+
+func checkMethod(rw http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		rw.Header().Set("Allow", method)
+		writeJSON(rw, http.StatusMethodNotAllowed, ErrorResponse{Error: fmt.Sprintf("httpserver: method %s is not allowed", r.Method)})
+		return false
+	}
+	return true
+}
+
+func writeError(rw http.ResponseWriter, err error) {
+	// Get code
+	code := http.StatusInternalServerError
+	if errors.Is(err, astitello.ErrInvalidArgument) {
+		code = http.StatusBadRequest
+	} else if errors.Is(err, astitello.ErrNotConnected) {
+		code = http.StatusConflict
+	}
+
+	// Write
+	writeJSON(rw, code, ErrorResponse{Error: err.Error()})
+}
+
+func writeJSON(rw http.ResponseWriter, code int, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	json.NewEncoder(rw).Encode(v)
+}
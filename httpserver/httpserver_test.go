@@ -0,0 +1,124 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/go-astitello"
+)
+
+type mockedDrone struct {
+	cmds []string
+	err  error
+	hs   map[string][]astikit.EventerHandler
+	s    astitello.State
+}
+
+func newMockedDrone() *mockedDrone {
+	return &mockedDrone{hs: make(map[string][]astikit.EventerHandler)}
+}
+
+func (d *mockedDrone) On(name string, h astikit.EventerHandler) { d.hs[name] = append(d.hs[name], h) }
+
+func (d *mockedDrone) dispatch(name string, payload interface{}) {
+	for _, h := range d.hs[name] {
+		h(payload)
+	}
+}
+
+func (d *mockedDrone) move(name string) func(x int) error {
+	return func(x int) error {
+		d.cmds = append(d.cmds, fmt.Sprintf("%s %d", name, x))
+		return d.err
+	}
+}
+
+func (d *mockedDrone) Back(x int) error                   { return d.move("back")(x) }
+func (d *mockedDrone) Down(x int) error                   { return d.move("down")(x) }
+func (d *mockedDrone) Forward(x int) error                { return d.move("forward")(x) }
+func (d *mockedDrone) Left(x int) error                   { return d.move("left")(x) }
+func (d *mockedDrone) Right(x int) error                  { return d.move("right")(x) }
+func (d *mockedDrone) RotateClockwise(x int) error        { return d.move("cw")(x) }
+func (d *mockedDrone) RotateCounterClockwise(x int) error { return d.move("ccw")(x) }
+func (d *mockedDrone) State() astitello.State             { return d.s }
+func (d *mockedDrone) Up(x int) error                     { return d.move("up")(x) }
+
+func (d *mockedDrone) Land() error {
+	d.cmds = append(d.cmds, "land")
+	return d.err
+}
+
+func (d *mockedDrone) TakeOff() error {
+	d.cmds = append(d.cmds, "takeoff")
+	return d.err
+}
+
+func TestServer(t *testing.T) {
+	// Create server
+	d := newMockedDrone()
+	d.s = astitello.State{Battery: 80, Height: 10}
+	s := New(d)
+
+	// Loop through requests
+	for _, v := range []struct {
+		body   string
+		cmds   []string
+		code   int
+		err    error
+		method string
+		path   string
+	}{
+		{method: http.MethodPost, path: "/takeoff", code: http.StatusNoContent, cmds: []string{"takeoff"}},
+		{method: http.MethodPost, path: "/land", code: http.StatusNoContent, cmds: []string{"land"}},
+		{method: http.MethodGet, path: "/land", code: http.StatusMethodNotAllowed},
+		{method: http.MethodPost, path: "/move", body: `{"direction":"forward","value":50}`, code: http.StatusNoContent, cmds: []string{"forward 50"}},
+		{method: http.MethodPost, path: "/move", body: `{"direction":"ccw","value":90}`, code: http.StatusNoContent, cmds: []string{"ccw 90"}},
+		{method: http.MethodPost, path: "/move", body: `{"direction":"sideways","value":50}`, code: http.StatusBadRequest},
+		{method: http.MethodPost, path: "/move", body: `{`, code: http.StatusBadRequest},
+		{method: http.MethodPost, path: "/move", body: `{"direction":"up","value":5}`, code: http.StatusBadRequest, cmds: []string{"up 5"}, err: fmt.Errorf("astitello: 5 is too low: %w", astitello.ErrInvalidArgument)},
+		{method: http.MethodPost, path: "/takeoff", code: http.StatusConflict, cmds: []string{"takeoff"}, err: astitello.ErrNotConnected},
+		{method: http.MethodPost, path: "/takeoff", code: http.StatusInternalServerError, cmds: []string{"takeoff"}, err: fmt.Errorf("test: error")},
+	} {
+		// Serve
+		d.cmds = nil
+		d.err = v.err
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(v.method, v.path, strings.NewReader(v.body)))
+
+		// Check
+		if e, g := v.code, rec.Code; e != g {
+			t.Errorf("%s %s: expected %d, got %d", v.method, v.path, e, g)
+		}
+		if e, g := v.cmds, d.cmds; !reflect.DeepEqual(e, g) {
+			t.Errorf("%s %s: expected %+v, got %+v", v.method, v.path, e, g)
+		}
+		if rec.Code >= http.StatusBadRequest {
+			var b ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&b); err != nil {
+				t.Errorf("%s %s: unmarshaling failed: %s", v.method, v.path, err)
+			} else if b.Error == "" {
+				t.Errorf("%s %s: expected an error message", v.method, v.path)
+			}
+		}
+	}
+
+	// State
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/state", nil))
+	if e, g := http.StatusOK, rec.Code; e != g {
+		t.Errorf("expected %d, got %d", e, g)
+	}
+	var st astitello.State
+	if err := json.NewDecoder(rec.Body).Decode(&st); err != nil {
+		t.Error(fmt.Errorf("test: unmarshaling failed: %w", err))
+	}
+	if e, g := d.s, st; !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+}
@@ -0,0 +1,99 @@
+package httpserver
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Defaults
+var (
+	websocketBufferSize   = 16
+	websocketWriteTimeout = 5 * time.Second
+)
+
+type websocketClient struct {
+	c    *websocket.Conn
+	ch   chan interface{}
+	once sync.Once
+}
+
+func newWebsocketClient(c *websocket.Conn) *websocketClient {
+	return &websocketClient{
+		c:  c,
+		ch: make(chan interface{}, websocketBufferSize),
+	}
+}
+
+func (c *websocketClient) close() {
+	c.once.Do(func() { c.c.Close() })
+}
+
+func (s *Server) addWebsocketClient(c *websocketClient) {
+	s.mw.Lock()
+	defer s.mw.Unlock()
+	s.ws[c] = true
+}
+
+func (s *Server) removeWebsocketClient(c *websocketClient) {
+	s.mw.Lock()
+	defer s.mw.Unlock()
+	delete(s.ws, c)
+}
+
+// broadcast never blocks: clients that can't keep up are disconnected
+func (s *Server) broadcast(v interface{}) {
+	s.mw.Lock()
+	defer s.mw.Unlock()
+	for c := range s.ws {
+		select {
+		case c.ch <- v:
+		default:
+			delete(s.ws, c)
+			c.close()
+		}
+	}
+}
+
+func (s *Server) handleWebsocket(rw http.ResponseWriter, r *http.Request) {
+	// Upgrade
+	conn, err := s.u.Upgrade(rw, r, nil)
+	if err != nil {
+		// Upgrade has already written the error to the response
+		return
+	}
+
+	// Create client
+	c := newWebsocketClient(conn)
+	defer c.close()
+
+	// Add client
+	s.addWebsocketClient(c)
+	defer s.removeWebsocketClient(c)
+
+	// Read in a goroutine so that closing the connection is detected and control frames are handled
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Write
+	for {
+		select {
+		case v := <-c.ch:
+			conn.SetWriteDeadline(time.Now().Add(websocketWriteTimeout))
+			if err := conn.WriteJSON(v); err != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
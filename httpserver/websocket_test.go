@@ -0,0 +1,97 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astitello"
+	"github.com/gorilla/websocket"
+)
+
+func websocketClients(s *Server) int {
+	s.mw.Lock()
+	defer s.mw.Unlock()
+	return len(s.ws)
+}
+
+func waitForWebsocketClients(t *testing.T, s *Server, n int) {
+	for i := 0; i < 100; i++ {
+		if websocketClients(s) == n {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("expected %d clients, got %d", n, websocketClients(s))
+}
+
+func TestWebsocket(t *testing.T) {
+	// Create server
+	d := newMockedDrone()
+	s := New(d)
+	hs := httptest.NewServer(s)
+	defer hs.Close()
+
+	// Connect clients
+	var cs []*websocket.Conn
+	for i := 0; i < 2; i++ {
+		c, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(hs.URL, "http")+"/ws", nil)
+		if err != nil {
+			t.Fatal(fmt.Errorf("test: dialing failed: %w", err))
+		}
+		defer c.Close()
+		cs = append(cs, c)
+	}
+	waitForWebsocketClients(t, s, 2)
+
+	// Dispatch state
+	e := astitello.State{Battery: 80, Height: 10}
+	d.dispatch(astitello.StateEvent, e)
+
+	// Read
+	for _, c := range cs {
+		c.SetReadDeadline(time.Now().Add(time.Second))
+		var g astitello.State
+		if err := c.ReadJSON(&g); err != nil {
+			t.Error(fmt.Errorf("test: reading failed: %w", err))
+		} else if !reflect.DeepEqual(e, g) {
+			t.Errorf("expected %+v, got %+v", e, g)
+		}
+	}
+
+	// Disconnect
+	cs[0].Close()
+	waitForWebsocketClients(t, s, 1)
+}
+
+func TestWebsocketSlowConsumer(t *testing.T) {
+	// Create server
+	s := New(newMockedDrone())
+	hs := httptest.NewServer(s)
+	defer hs.Close()
+
+	// Create a client nobody writes to
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(hs.URL, "http")+"/ws", nil)
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: dialing failed: %w", err))
+	}
+	c := newWebsocketClient(conn)
+	s.addWebsocketClient(c)
+	waitForWebsocketClients(t, s, 2)
+
+	// Overflow its buffer
+	for i := 0; i <= websocketBufferSize; i++ {
+		s.broadcast(astitello.State{})
+	}
+
+	// Slow client should have been dropped
+	s.mw.Lock()
+	_, ok := s.ws[c]
+	s.mw.Unlock()
+	if ok {
+		t.Error("expected slow client to be dropped")
+	}
+}
@@ -0,0 +1,151 @@
+package astitello
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/asticode/go-astikit"
+)
+
+// Interval at which the LinkQuality event is dispatched
+var linkQualityInterval = time.Second
+
+// Weight of the latest sample in the exponential moving averages
+const linkQualityAlpha = 0.2
+
+// RTTs above which the link is considered good and unusable
+const (
+	linkQualityGoodRTT     = 100 * time.Millisecond
+	linkQualityUnusableRTT = time.Second
+)
+
+// LinkQuality represents the quality of the link with the drone
+type LinkQuality struct {
+	RTT       time.Duration `json:"rtt"`        // The exponential moving average of cmds round-trip time, 0 until a response has been received
+	Score     int           `json:"score"`      // A summary of RTT and VideoLoss, from 0 (unusable) to 100 (perfect)
+	VideoLoss float64       `json:"video_loss"` // The exponential moving average of the ratio of video packets lost, from 0 to 1
+}
+
+// LinkQualityEventHandler returns the proper EventHandler for the LinkQuality event
+func LinkQualityEventHandler(f func(q LinkQuality)) astikit.EventerHandler {
+	return func(payload interface{}) {
+		f(payload.(LinkQuality))
+	}
+}
+
+// LinkQuality returns the quality of the link with the drone
+// RTT is updated every time a cmd response is received and VideoLoss every time the LinkQuality event is
+// dispatched, which only happens while the drone is started.
+func (d *Drone) LinkQuality() LinkQuality {
+	// Lock
+	d.mlq.Lock()
+	defer d.mlq.Unlock()
+
+	// Copy
+	q := d.lq.q
+	q.Score = linkQualityScore(q)
+	return q
+}
+
+type linkQuality struct {
+	dropped uint64 // Dropped video stat when VideoLoss was last updated
+	packets uint64 // Packets video stat when VideoLoss was last updated
+	q       LinkQuality
+}
+
+func linkQualityScore(q LinkQuality) int {
+	// Get RTT factor
+	f := 1.0
+	if q.RTT > linkQualityUnusableRTT {
+		f = 0
+	} else if q.RTT > linkQualityGoodRTT {
+		f = 1 - float64(q.RTT-linkQualityGoodRTT)/float64(linkQualityUnusableRTT-linkQualityGoodRTT)
+	}
+	return int(100 * f * (1 - q.VideoLoss))
+}
+
+func movingAverage(previous, sample float64) float64 {
+	return linkQualityAlpha*sample + (1-linkQualityAlpha)*previous
+}
+
+func (d *Drone) resetLinkQuality() {
+	d.mlq.Lock()
+	defer d.mlq.Unlock()
+	d.lq = linkQuality{}
+}
+
+func (d *Drone) updateRTT(rtt time.Duration) {
+	// Lock
+	d.mlq.Lock()
+	defer d.mlq.Unlock()
+
+	// First sample
+	if d.lq.q.RTT == 0 {
+		d.lq.q.RTT = rtt
+		return
+	}
+
+	// Update average
+	d.lq.q.RTT = time.Duration(movingAverage(float64(d.lq.q.RTT), float64(rtt)))
+}
+
+func (d *Drone) updateVideoLoss() {
+	// Get stats
+	dropped, packets := atomic.LoadUint64(&d.vs.Dropped), atomic.LoadUint64(&d.vs.Packets)
+
+	// Lock
+	d.mlq.Lock()
+	defer d.mlq.Unlock()
+
+	// Get deltas
+	previousDropped, previousPackets := d.lq.dropped, d.lq.packets
+	d.lq.dropped, d.lq.packets = dropped, packets
+
+	// Stats have been reset or no video has been received
+	if dropped < previousDropped || packets <= previousPackets {
+		return
+	}
+
+	// Get ratio
+	r := float64(dropped-previousDropped) / float64(packets-previousPackets)
+	if r > 1 {
+		r = 1
+	}
+
+	// Update average
+	d.lq.q.VideoLoss = movingAverage(d.lq.q.VideoLoss, r)
+}
+
+func (d *Drone) startLinkQuality() {
+	d.wg.Add(1)
+	go d.dispatchLinkQuality()
+}
+
+func (d *Drone) dispatchLinkQuality() {
+	// Make sure to signal the goroutine is done
+	defer d.wg.Done()
+
+	// Create ticker
+	t := time.NewTicker(linkQualityInterval)
+	defer t.Stop()
+
+	// Loop
+	for {
+		select {
+		case <-t.C:
+			// Update video loss
+			d.updateVideoLoss()
+			q := d.LinkQuality()
+
+			// Adapt bitrate
+			if d.o.adaptiveBitrate {
+				d.adaptBitrate(q)
+			}
+
+			// Dispatch
+			d.e.Dispatch(LinkQualityEvent, q)
+		case <-d.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package astitello
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLinkQualityScore(t *testing.T) {
+	for _, v := range []struct {
+		e int
+		q LinkQuality
+	}{
+		{e: 100, q: LinkQuality{}},
+		{e: 100, q: LinkQuality{RTT: linkQualityGoodRTT}},
+		{e: 50, q: LinkQuality{RTT: 550 * time.Millisecond}},
+		{e: 0, q: LinkQuality{RTT: 2 * time.Second}},
+		{e: 75, q: LinkQuality{VideoLoss: 0.25}},
+		{e: 37, q: LinkQuality{RTT: 550 * time.Millisecond, VideoLoss: 0.25}},
+	} {
+		if g := linkQualityScore(v.q); g != v.e {
+			t.Errorf("%+v: expected %d, got %d", v.q, v.e, g)
+		}
+	}
+}
+
+func TestLinkQuality(t *testing.T) {
+	// Update defaults
+	i := linkQualityInterval
+	linkQualityInterval = 5 * time.Millisecond
+	defer func() { linkQualityInterval = i }()
+
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Handle events
+	qs := make(chan LinkQuality, 1)
+	d.On(LinkQualityEvent, LinkQualityEventHandler(func(q LinkQuality) {
+		select {
+		case qs <- q:
+		default:
+		}
+	}))
+
+	// Delay responses
+	var delay int64
+	c.mt.Lock()
+	h := c.h
+	c.h = func(cmd []byte) []byte {
+		if string(cmd) == "wifi?" {
+			time.Sleep(time.Duration(atomic.LoadInt64(&delay)))
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+
+	// Average should converge towards the latest delay
+	for _, v := range []time.Duration{0, 40 * time.Millisecond} {
+		atomic.StoreInt64(&delay, int64(v))
+		for idx := 0; idx < 15; idx++ {
+			if _, err := d.Ping(context.Background()); err != nil {
+				t.Fatal(fmt.Errorf("test: pinging failed: %w", err))
+			}
+		}
+		if g := d.LinkQuality().RTT; g < v*9/10 || g > v+20*time.Millisecond {
+			t.Errorf("expected rtt close to %s, got %s", v, g)
+		}
+	}
+
+	// Simulate video loss
+	atomic.StoreUint64(&d.vs.Packets, 10)
+	atomic.StoreUint64(&d.vs.Dropped, 5)
+
+	// Wait for event
+	for n := time.Now(); ; {
+		select {
+		case q := <-qs:
+			if q.VideoLoss > 0 {
+				if q.VideoLoss > 0.5 || q.Score >= 100 {
+					t.Errorf("invalid link quality %+v", q)
+				}
+				return
+			} else if time.Since(n) > time.Second {
+				t.Fatalf("expected video loss, got %+v", q)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected link quality event")
+		}
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/asticode/go-astitello/metrics
+
+go 1.13
+
+require (
+	github.com/asticode/go-astikit v0.2.0
+	github.com/asticode/go-astitello v0.1.0
+	github.com/prometheus/client_golang v1.11.1
+)
+
+// Only used when developing locally, it is ignored by modules depending on this one
+replace github.com/asticode/go-astitello => ../
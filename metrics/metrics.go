@@ -0,0 +1,151 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/go-astitello"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Drone represents the drone methods the collector relies on. *astitello.Drone implements it.
+type Drone interface {
+	Connected() bool
+	On(name string, h astikit.EventerHandler)
+	Wifi() (int, error)
+}
+
+// Interval at which the wifi SNR is refreshed
+var wifiRefreshInterval = 5 * time.Second
+
+// Collector represents a prometheus collector exposing the drone's state
+// Metrics are absent when the drone is not connected, and state metrics are absent until the first state
+// has been received
+// Collecting never sends cmds to the drone: the wifi SNR is queried in the background, at most every 5s, when
+// states are received, and the latest value is served.
+type Collector struct {
+	barometer          *prometheus.Desc
+	battery            *prometheus.Desc
+	d                  Drone
+	flightTime         *prometheus.Desc
+	height             *prometheus.Desc
+	highestTemperature *prometheus.Desc
+	lowestTemperature  *prometheus.Desc
+	m                  *sync.Mutex // Locks refreshing, s, snr and snrAt
+	refreshing         bool
+	s                  *astitello.State
+	snr                *int
+	snrAt              time.Time
+	wifi               *prometheus.Desc
+}
+
+// NewCollector creates a new collector and starts updating it from the drone's state events
+// Event handlers are removed when the drone is closed, therefore a new collector is needed once it's restarted.
+func NewCollector(d Drone) (c *Collector) {
+	// Create collector
+	c = &Collector{
+		barometer:          prometheus.NewDesc("tello_barometer_centimeters", "The barometer measurement", nil, nil),
+		battery:            prometheus.NewDesc("tello_battery_percent", "The percentage of the current battery level", nil, nil),
+		d:                  d,
+		flightTime:         prometheus.NewDesc("tello_flight_time_seconds", "The amount of time the motor has been used", nil, nil),
+		height:             prometheus.NewDesc("tello_height_centimeters", "The height", nil, nil),
+		highestTemperature: prometheus.NewDesc("tello_highest_temperature_celsius", "The highest temperature", nil, nil),
+		lowestTemperature:  prometheus.NewDesc("tello_lowest_temperature_celsius", "The lowest temperature", nil, nil),
+		m:                  &sync.Mutex{},
+		wifi:               prometheus.NewDesc("tello_wifi_snr", "The wifi SNR", nil, nil),
+	}
+
+	// Handle state
+	d.On(astitello.StateEvent, astitello.StateEventHandler(func(s astitello.State) {
+		c.m.Lock()
+		defer c.m.Unlock()
+		c.s = &s
+		c.refreshWifi()
+	}))
+
+	// Make sure state and wifi SNR don't become stale
+	d.On(astitello.DisconnectEvent, func(interface{}) {
+		c.m.Lock()
+		defer c.m.Unlock()
+		c.s = nil
+		c.snr = nil
+		c.snrAt = time.Time{}
+	})
+	return
+}
+
+// refreshWifi queries the wifi SNR in a goroutine, so that the cmd doesn't block event handlers, unless it has
+// been refreshed recently or is being refreshed. It must be called while c.m is locked.
+func (c *Collector) refreshWifi() {
+	// No need to refresh
+	if c.refreshing || time.Since(c.snrAt) < wifiRefreshInterval {
+		return
+	}
+	c.refreshing = true
+
+	// Refresh
+	go func() {
+		// Query
+		snr, err := c.d.Wifi()
+
+		// Lock
+		c.m.Lock()
+		defer c.m.Unlock()
+
+		// Update
+		c.refreshing = false
+		c.snrAt = time.Now()
+		c.snr = nil
+		if err == nil {
+			c.snr = &snr
+		}
+	}()
+}
+
+// Describe implements the prometheus.Collector interface
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		c.barometer,
+		c.battery,
+		c.flightTime,
+		c.height,
+		c.highestTemperature,
+		c.lowestTemperature,
+		c.wifi,
+	} {
+		ch <- d
+	}
+}
+
+// Collect implements the prometheus.Collector interface
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	// Drone is not connected
+	if !c.d.Connected() {
+		return
+	}
+
+	// Get state and wifi SNR
+	c.m.Lock()
+	s, snr := c.s, c.snr
+	c.m.Unlock()
+
+	// State
+	if s != nil {
+		for d, v := range map[*prometheus.Desc]float64{
+			c.barometer:          s.Barometer,
+			c.battery:            float64(s.Battery),
+			c.flightTime:         float64(s.FlightTime),
+			c.height:             float64(s.Height),
+			c.highestTemperature: float64(s.HighestTemperature),
+			c.lowestTemperature:  float64(s.LowestTemperature),
+		} {
+			ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v)
+		}
+	}
+
+	// Wifi
+	if snr != nil {
+		ch <- prometheus.MustNewConstMetric(c.wifi, prometheus.GaugeValue, float64(*snr))
+	}
+}
@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/go-astitello"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type mockedDrone struct {
+	connected bool
+	hs        map[string][]astikit.EventerHandler
+	wifis     int32 // Updated atomically
+}
+
+func newMockedDrone() *mockedDrone {
+	return &mockedDrone{hs: make(map[string][]astikit.EventerHandler)}
+}
+
+func (d *mockedDrone) Connected() bool { return d.connected }
+
+func (d *mockedDrone) On(name string, h astikit.EventerHandler) { d.hs[name] = append(d.hs[name], h) }
+
+func (d *mockedDrone) Wifi() (int, error) {
+	atomic.AddInt32(&d.wifis, 1)
+	return 90, nil
+}
+
+func (d *mockedDrone) dispatch(name string, payload interface{}) {
+	for _, h := range d.hs[name] {
+		h(payload)
+	}
+}
+
+func gather(t *testing.T, r *prometheus.Registry) map[string]float64 {
+	mfs, err := r.Gather()
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: gathering failed: %w", err))
+	}
+	m := make(map[string]float64)
+	for _, mf := range mfs {
+		m[mf.GetName()] = mf.GetMetric()[0].GetGauge().GetValue()
+	}
+	return m
+}
+
+func TestCollector(t *testing.T) {
+	// Create collector
+	d := newMockedDrone()
+	c := NewCollector(d)
+
+	// Register
+	r := prometheus.NewRegistry()
+	if err := r.Register(c); err != nil {
+		t.Fatal(fmt.Errorf("test: registering failed: %w", err))
+	}
+
+	// Not connected
+	if g := gather(t, r); len(g) > 0 {
+		t.Errorf("expected no metrics, got %+v", g)
+	}
+
+	// Connected without state
+	d.connected = true
+	if g := gather(t, r); len(g) > 0 {
+		t.Errorf("expected no metrics, got %+v", g)
+	}
+
+	// Connected with state
+	d.dispatch(astitello.StateEvent, astitello.State{Barometer: 1.5, Battery: 2, FlightTime: 3, Height: 4, HighestTemperature: 5, LowestTemperature: 6})
+	e := map[string]float64{
+		"tello_barometer_centimeters":       1.5,
+		"tello_battery_percent":             2,
+		"tello_flight_time_seconds":         3,
+		"tello_height_centimeters":          4,
+		"tello_highest_temperature_celsius": 5,
+		"tello_lowest_temperature_celsius":  6,
+		"tello_wifi_snr":                    90,
+	}
+	var g map[string]float64
+	for n := time.Now(); time.Since(n) < time.Second; time.Sleep(time.Millisecond) {
+		if g = gather(t, r); reflect.DeepEqual(e, g) {
+			break
+		}
+	}
+	if !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+
+	// Collecting and states received shortly after should not query the wifi SNR
+	d.dispatch(astitello.StateEvent, astitello.State{})
+	for idx := 0; idx < 3; idx++ {
+		gather(t, r)
+	}
+	if e, g := int32(1), atomic.LoadInt32(&d.wifis); e != g {
+		t.Errorf("expected %d wifi queries, got %d", e, g)
+	}
+
+	// Disconnected
+	d.dispatch(astitello.DisconnectEvent, nil)
+	if g := gather(t, r); len(g) > 0 {
+		t.Errorf("expected no metrics, got %+v", g)
+	}
+}
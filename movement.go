@@ -0,0 +1,56 @@
+package astitello
+
+import (
+	"math"
+	"time"
+)
+
+// Movement modes
+const (
+	// MovementModeCommand makes helpers send distance cmds such as "go" or "up", which are precise since the
+	// drone uses its sensors to reach the destination, but block until the movement is done and can't move less
+	// than 20cm
+	MovementModeCommand MovementMode = "command"
+	// MovementModeRC makes helpers set sticks positions for a computed duration, which is smooth and can be
+	// corrected continuously, but imprecise since it assumes a stick value of n makes the drone fly at roughly
+	// n cm/s
+	MovementModeRC MovementMode = "rc"
+)
+
+// MovementMode represents how helpers such as Move and HoldAltitude move the drone
+type MovementMode string
+
+// moveRC flies dx cm forward, dy cm left and dz cm up by setting sticks positions for as long as it should take
+// at speed (cm/s). Sticks are set back to their neutral position early if the drone is closed in the meantime.
+func (d *Drone) moveRC(dx, dy, dz, speed int) (err error) {
+	// Validate
+	if err = validateGo(dx, dy, dz, speed); err != nil {
+		return
+	}
+
+	// Check flight state
+	if err = d.checkAirborne(); err != nil {
+		return
+	}
+
+	// Get session context
+	ctx := d.sessionContext()
+	if ctx == nil {
+		err = ErrNotConnected
+		return
+	}
+
+	// Get sticks, lr is positive when flying right
+	distance := math.Sqrt(float64(dx*dx + dy*dy + dz*dz))
+	stick := func(v int) int { return int(math.Round(float64(v) / distance * float64(speed))) }
+	lr, fb, ud := stick(-dy), stick(dx), stick(dz)
+
+	// Set sticks
+	if err = d.SetSticksFor(ctx, lr, fb, ud, 0, time.Duration(distance/float64(speed)*float64(time.Second))); err != nil {
+		return
+	}
+
+	// Update displacement
+	d.addMove(dx, dy, dz)
+	return
+}
@@ -0,0 +1,175 @@
+package astitello
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMovementMode(t *testing.T) {
+	// Update defaults
+	i := rcLoopInterval
+	rcLoopInterval = 5 * time.Millisecond
+	defer func() { rcLoopInterval = i }()
+
+	for _, v := range []struct {
+		e    []string
+		mode MovementMode
+	}{
+		{e: []string{"go 30 -40 0 100"}},
+		{e: []string{"go 30 -40 0 100"}, mode: MovementModeCommand},
+		{e: []string{"rc 80 60 0 0", "rc 0 0 0 0"}, mode: MovementModeRC},
+	} {
+		t.Run(fmt.Sprintf("mode %q", v.mode), func(t *testing.T) {
+			// Set up and start
+			d, c, _, _, teardown := setupAndStart(t, WithMovementMode(v.mode))
+			defer teardown()
+
+			// Respond to go cmd
+			c.mt.Lock()
+			h := c.h
+			c.h = func(cmd []byte) []byte {
+				if string(cmd) == "go 30 -40 0 100" {
+					return []byte("ok")
+				}
+				return h(cmd)
+			}
+			c.mt.Unlock()
+
+			// Move
+			n := time.Now()
+			if err := d.Move(30, -40, 0, 100); err != nil {
+				t.Error(fmt.Errorf("test: moving failed: %w", err))
+			}
+
+			// Sticks should be set for as long as the movement should take
+			if v.mode == MovementModeRC {
+				if e, g := 500*time.Millisecond, time.Since(n); g < e {
+					t.Errorf("expected at least %s, got %s", e, g)
+				}
+			}
+
+			// Wait for the last cmd, since rc cmds have no response
+			var g []string
+			for n := time.Now(); time.Since(n) < time.Second; time.Sleep(time.Millisecond) {
+				// Remove duplicates and the cmds sent on connect
+				g = nil
+				for _, cmd := range c.received()[3:] {
+					if len(g) == 0 || g[len(g)-1] != cmd {
+						g = append(g, cmd)
+					}
+				}
+				if reflect.DeepEqual(v.e, g) {
+					break
+				}
+			}
+			if !reflect.DeepEqual(v.e, g) {
+				t.Errorf("expected %+v, got %+v", v.e, g)
+			}
+		})
+	}
+}
+
+func TestMoveRCClose(t *testing.T) {
+	// Set up and start
+	d, _, _, _, teardown := setupAndStart(t, WithMovementMode(MovementModeRC))
+	defer teardown()
+
+	// Move for 50s
+	errs := make(chan error)
+	go func() { errs <- d.Move(500, 0, 0, 10) }()
+
+	// Movement should stop once the drone is closed
+	time.Sleep(20 * time.Millisecond)
+	d.Close()
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected %s, got %v", context.Canceled, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected movement to stop")
+	}
+}
+
+func TestHoldAltitudeCommandMode(t *testing.T) {
+	// Update defaults
+	i := altitudeHoldInterval
+	altitudeHoldInterval = 5 * time.Millisecond
+	defer func() { altitudeHoldInterval = i }()
+
+	// Set up and start
+	d, c, s, _, teardown := setupAndStart(t, WithMovementMode(MovementModeCommand))
+	defer teardown()
+
+	// Don't respond to up cmd, as if the movement was taking long
+	c.mt.Lock()
+	h := c.h
+	c.h = func(cmd []byte) []byte {
+		if string(cmd) == "up 83" {
+			return nil
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+
+	// Send state
+	if _, err := s.conn.Write([]byte(strState)); err != nil {
+		t.Fatal(fmt.Errorf("test: writing state failed: %w", err))
+	}
+	if _, err := d.WaitForState(context.Background()); err != nil {
+		t.Fatal(fmt.Errorf("test: waiting for state failed: %w", err))
+	}
+
+	// Hold altitude
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() { errs <- d.HoldAltitude(ctx, 100) }()
+
+	// Wait for correction
+	for n, found := time.Now(), false; !found; time.Sleep(time.Millisecond) {
+		for _, cmd := range c.received() {
+			if cmd == "up 83" {
+				found = true
+			}
+		}
+		if !found && time.Since(n) > time.Second {
+			t.Fatalf("expected up 83, got %+v", c.received())
+		}
+	}
+
+	// Stop while the correction is still running
+	cancel()
+	select {
+	case err := <-errs:
+		if err != nil {
+			t.Error(fmt.Errorf("test: holding altitude failed: %w", err))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected holding altitude to stop")
+	}
+
+	// Only distance cmds should have been sent after connecting
+	for _, cmd := range c.received()[3:] {
+		if cmd != "up 83" && cmd != "rc 0 0 0 0" {
+			t.Errorf("unexpected cmd %s", cmd)
+		}
+	}
+
+	// Small differences should be ignored
+	for _, v := range []struct {
+		delta int
+		e     int
+	}{
+		{delta: 19, e: 0},
+		{delta: -20, e: 20},
+		{delta: 600, e: 500},
+	} {
+		if g := altitudeDistance(v.delta); g != v.e {
+			t.Errorf("%d: expected %d, got %d", v.delta, v.e, g)
+		}
+	}
+}
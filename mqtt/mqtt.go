@@ -0,0 +1,147 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/go-astitello"
+)
+
+// Defaults
+var (
+	defaultInterval = time.Second
+	defaultTopic    = "tello/telemetry"
+)
+
+// Drone represents the drone methods the bridge relies on. *astitello.Drone implements it.
+type Drone interface {
+	On(name string, h astikit.EventerHandler)
+}
+
+// Publisher represents an MQTT client capable of publishing payloads. Wrap the client of your choice
+// to implement it.
+type Publisher interface {
+	Publish(topic string, payload []byte) error
+}
+
+// Connector can be implemented by publishers in which case the bridge uses it to reconnect them after
+// publishing failed
+type Connector interface {
+	Connect() error
+}
+
+// BridgeOptions represents bridge options
+type BridgeOptions struct {
+	// Minimum interval between two publications. Defaults to 1s.
+	Interval time.Duration
+	Logger   astikit.StdLogger
+	// Defaults to "tello/telemetry"
+	Topic string
+}
+
+// Bridge represents an object publishing the drone's state as JSON telemetry to an MQTT topic
+type Bridge struct {
+	l     astikit.SeverityLogger
+	m     *sync.Mutex // Locks s
+	o     BridgeOptions
+	p     Publisher
+	s     *astitello.State
+	stale bool // Whether the publisher needs to be reconnected
+}
+
+// NewBridge creates a new bridge and starts keeping track of the drone's state
+// Event handlers are removed when the drone is closed, therefore a new bridge is needed once it's restarted.
+func NewBridge(d Drone, p Publisher, o BridgeOptions) (b *Bridge) {
+	// Default options
+	if o.Interval <= 0 {
+		o.Interval = defaultInterval
+	}
+	if o.Topic == "" {
+		o.Topic = defaultTopic
+	}
+
+	// Create bridge
+	b = &Bridge{
+		l: astikit.AdaptStdLogger(o.Logger),
+		m: &sync.Mutex{},
+		o: o,
+		p: p,
+	}
+
+	// Handle state
+	d.On(astitello.StateEvent, astitello.StateEventHandler(func(s astitello.State) {
+		b.m.Lock()
+		defer b.m.Unlock()
+		b.s = &s
+	}))
+	return
+}
+
+// Start publishes the latest state, if any new, at the bridge's interval until the context is done
+// It is blocking
+func (b *Bridge) Start(ctx context.Context) {
+	// Create ticker
+	t := time.NewTicker(b.o.Interval)
+	defer t.Stop()
+
+	// Loop
+	for {
+		select {
+		case <-t.C:
+			if err := b.publish(); err != nil {
+				b.l.Error(fmt.Errorf("mqtt: publishing failed: %w", err))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (b *Bridge) publish() (err error) {
+	// Get state
+	b.m.Lock()
+	s := b.s
+	b.m.Unlock()
+
+	// No new state
+	if s == nil {
+		return
+	}
+
+	// Reconnect
+	if b.stale {
+		if c, ok := b.p.(Connector); ok {
+			if err = c.Connect(); err != nil {
+				err = fmt.Errorf("mqtt: reconnecting failed: %w", err)
+				return
+			}
+		}
+		b.stale = false
+	}
+
+	// Marshal
+	var p []byte
+	if p, err = json.Marshal(s); err != nil {
+		err = fmt.Errorf("mqtt: marshaling failed: %w", err)
+		return
+	}
+
+	// Publish
+	if err = b.p.Publish(b.o.Topic, p); err != nil {
+		b.stale = true
+		err = fmt.Errorf("mqtt: publishing to %s failed: %w", b.o.Topic, err)
+		return
+	}
+
+	// Make sure the same state is not published twice
+	b.m.Lock()
+	if b.s == s {
+		b.s = nil
+	}
+	b.m.Unlock()
+	return
+}
@@ -0,0 +1,107 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astikit"
+	"github.com/asticode/go-astitello"
+)
+
+type mockedDrone struct {
+	hs map[string][]astikit.EventerHandler
+}
+
+func (d *mockedDrone) On(name string, h astikit.EventerHandler) {
+	d.hs[name] = append(d.hs[name], h)
+}
+
+func (d *mockedDrone) dispatch(name string, payload interface{}) {
+	for _, h := range d.hs[name] {
+		h(payload)
+	}
+}
+
+type mockedPublisher struct {
+	connects int
+	fail     bool
+	m        *sync.Mutex
+	ps       []string
+	topics   []string
+}
+
+func (p *mockedPublisher) Connect() error {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.connects++
+	return nil
+}
+
+func (p *mockedPublisher) Publish(topic string, payload []byte) error {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.fail {
+		p.fail = false
+		return errors.New("mqtt: failed")
+	}
+	p.ps = append(p.ps, string(payload))
+	p.topics = append(p.topics, topic)
+	return nil
+}
+
+func TestBridge(t *testing.T) {
+	// Create bridge
+	d := &mockedDrone{hs: make(map[string][]astikit.EventerHandler)}
+	p := &mockedPublisher{fail: true, m: &sync.Mutex{}}
+	b := NewBridge(d, p, BridgeOptions{Interval: time.Millisecond, Topic: "topic"})
+
+	// First publication fails, which should trigger a reconnection
+	d.dispatch(astitello.StateEvent, astitello.State{Battery: 1})
+	if err := b.publish(); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	// Several states are dispatched between two publications, only the latest one should be published
+	d.dispatch(astitello.StateEvent, astitello.State{Battery: 2})
+	if err := b.publish(); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	}
+
+	// No new state
+	if err := b.publish(); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	}
+
+	// Start
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go b.Start(ctx)
+	d.dispatch(astitello.StateEvent, astitello.State{Battery: 3})
+	for {
+		p.m.Lock()
+		n := len(p.ps)
+		p.m.Unlock()
+		if n == 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	// Check
+	p.m.Lock()
+	defer p.m.Unlock()
+	if e, g := 1, p.connects; e != g {
+		t.Errorf("expected %d, got %d", e, g)
+	}
+	if e := []string{"topic", "topic"}; !reflect.DeepEqual(e, p.topics) {
+		t.Errorf("expected %+v, got %+v", e, p.topics)
+	}
+	if e, g := `{"acceleration":{"x":0,"y":0,"z":0},"attitude":{"pitch_deg":0,"roll_deg":0,"yaw_deg":0},"baro_cm":0,"battery":2,"tof_cm":0,"flight_time_s":0,"height_cm":0,"temp_highest_c":0,"temp_lowest_c":0,"speed":{"x":0,"y":0,"z":0}}`, p.ps[0]; e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+}
@@ -0,0 +1,416 @@
+package astitello
+
+import "time"
+
+// Option represents a Drone option
+type Option func(o *options)
+
+type options struct {
+	adaptiveBitrate          bool
+	altitudeHoldGain         float64
+	altitudeHoldMax          int
+	cmdAddr                  string
+	commandRetries           int
+	connectRetries           int
+	connectRetryTimeout      time.Duration
+	connectTimeout           time.Duration
+	crashAcceleration        float64
+	crashDebounce            int
+	crashWindow              time.Duration
+	eventWorkers             int
+	flightDetectionDebounce  int
+	flightDetectionThreshold int
+	landOnClose              bool
+	manualHandshake          bool
+	minTakeoffBattery        int
+	movementMode             MovementMode
+	network                  string
+	preflightThresholds      PreflightThresholds
+	rcMaxRate                int
+	recorder                 *Recorder
+	reconnectBackoff         time.Duration
+	reconnectMaxAttempts     int
+	telemetryLostAction      TelemetryLostAction
+	telemetryTimeout         time.Duration
+	timeouts                 Timeouts
+	videoBufferSize          int
+	videoDecoder             Decoder
+	videoDropPolicy          VideoDropPolicy
+	videoFrames              bool
+	videoIdleTimeout         time.Duration
+	videoMTU                 int
+	videoOptional            bool
+	videoPackets             bool
+	videoReadBufferSize      int
+	videoReadSize            int
+	videoStartTimeout        time.Duration
+}
+
+func newOptions(opts []Option) (o options) {
+	// Default
+	o = options{
+		altitudeHoldGain:         1,
+		altitudeHoldMax:          50,
+		crashAcceleration:        3000,
+		crashDebounce:            2,
+		crashWindow:              2 * time.Second,
+		flightDetectionDebounce:  3,
+		flightDetectionThreshold: 10,
+		network:                  "udp4",
+		videoDropPolicy:          VideoDropPolicyOldest,
+		videoIdleTimeout:         time.Second,
+		videoMTU:                 1460,
+		videoPackets:             true,
+		videoReadSize:            2048,
+	}
+
+	// Loop through options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return
+}
+
+// WithAdaptiveBitrate makes the drone lower the video bitrate by 1Mbps when video loss, as reported by
+// LinkQuality, stays above 10% for 2 LinkQuality events, and raise it back when it stays below 2% for 5 events.
+// It assumes the drone streams at 5Mbps when video starts and only applies while video is streaming. Disabled by
+// default.
+func WithAdaptiveBitrate(enabled bool) Option {
+	return func(o *options) {
+		o.adaptiveBitrate = enabled
+	}
+}
+
+// WithAltitudeHold configures HoldAltitude: the ud stick is set to the height difference (cm) multiplied by gain,
+// clamped between -max and max. Since sticks values are between -100 and 100, max is clamped between 0 and 100.
+// Defaults to a gain of 1 and a max of 50.
+func WithAltitudeHold(gain float64, max int) Option {
+	return func(o *options) {
+		if max < 0 {
+			max = 0
+		} else if max > 100 {
+			max = 100
+		}
+		o.altitudeHoldGain = gain
+		o.altitudeHoldMax = max
+	}
+}
+
+// WithAutoReconnect makes the drone re-create its connections when reading from them fails repeatedly.
+// Up to maxAttempts attempts are made, waiting attempt*backoff before each of them. Disabled by default.
+func WithAutoReconnect(maxAttempts int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.reconnectBackoff = backoff
+		o.reconnectMaxAttempts = maxAttempts
+	}
+}
+
+// WithCommandAddr sets the address cmds are sent to, which is needed when the drone has joined an existing
+// network through ConnectToAP. Defaults to "192.168.10.1:8889".
+func WithCommandAddr(addr string) Option {
+	return func(o *options) {
+		o.cmdAddr = addr
+	}
+}
+
+// WithCommandRetries makes cmds be sent again up to n times when their response times out, which happens
+// when a single UDP datagram is lost. Only cmds that are safe to send twice are retried: queries, settings
+// and movements, but neither take off, land, flip nor emergency. Disabled by default.
+func WithCommandRetries(n int) Option {
+	return func(o *options) {
+		o.commandRetries = n
+	}
+}
+
+// WithConnectRetries makes Start() send the "command" handshake up to n times, waiting perAttempt for each
+// response, since a freshly powered drone often ignores the first attempts. Only timeouts are retried.
+// Defaults to a single attempt using the default timeout.
+func WithConnectRetries(n int, perAttempt time.Duration) Option {
+	return func(o *options) {
+		o.connectRetries = n
+		o.connectRetryTimeout = perAttempt
+	}
+}
+
+// WithConnectTimeout makes Start() fail with ErrTimeout when the drone hasn't acknowledged the "command"
+// handshake within the provided duration, retries included. Disabled by default.
+func WithConnectTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.connectTimeout = timeout
+	}
+}
+
+// WithCrashDetection configures how the Crash event is dispatched: a crash is detected while airborne when the
+// acceleration magnitude (0.001g, 1000 being the gravity) reaches acceleration, and then debounce consecutive
+// states report a zero height within window. Provide an acceleration <= 0 to disable detection. Defaults to
+// 3000 (3g), a debounce of 2 states and a 2s window.
+func WithCrashDetection(acceleration float64, window time.Duration, debounce int) Option {
+	return func(o *options) {
+		o.crashAcceleration = acceleration
+		o.crashDebounce = debounce
+		o.crashWindow = window
+	}
+}
+
+// WithEventWorkers makes event handlers run concurrently in up to n goroutines so that a slow handler doesn't
+// delay other events, such as high-rate State events. Handlers are still started in the order events are
+// dispatched, but they may be done in a different order, including handlers of the same event: handlers must
+// be safe for concurrent use. When all workers are busy, events wait for one of them to be available. Disabled
+// by default, in which case handlers run one after the other.
+func WithEventWorkers(n int) Option {
+	return func(o *options) {
+		o.eventWorkers = n
+	}
+}
+
+// WithFlightDetection configures how the Airborne and Grounded events are dispatched: the drone is
+// considered airborne once debounce consecutive states report a height greater than or equal to threshold
+// cm, and grounded once debounce consecutive states report a lower height. Provide a threshold <= 0 to
+// disable detection. Defaults to a 10cm threshold and a debounce of 3 states.
+func WithFlightDetection(threshold, debounce int) Option {
+	return func(o *options) {
+		o.flightDetectionDebounce = debounce
+		o.flightDetectionThreshold = threshold
+	}
+}
+
+// WithLandOnClose makes Close() send a land cmd before tearing down connections, which prevents the drone
+// from drifting away when the program exits unexpectedly. This is best-effort: Close() doesn't wait more
+// than a second for the response. Disabled by default.
+func WithLandOnClose(enabled bool) Option {
+	return func(o *options) {
+		o.landOnClose = enabled
+	}
+}
+
+// WithManualHandshake makes Start() set up connections without sending the "command" handshake, which must
+// then be sent with EnterSDKMode() before any other cmd. Disabled by default.
+func WithManualHandshake(enabled bool) Option {
+	return func(o *options) {
+		o.manualHandshake = enabled
+	}
+}
+
+// WithMinTakeoffBattery makes TakeOff() fail with ErrBatteryTooLow, without sending the cmd, when the
+// battery level is below pct %. The level is read from the latest state, or queried if no state has been
+// received yet. Disabled by default.
+func WithMinTakeoffBattery(pct int) Option {
+	return func(o *options) {
+		o.minTakeoffBattery = pct
+	}
+}
+
+// WithMovementMode sets how helpers move the drone: Move sends the go cmd and HoldAltitude sets sticks positions by
+// default. Check out MovementModeCommand and MovementModeRC for their tradeoffs.
+func WithMovementMode(m MovementMode) Option {
+	return func(o *options) {
+		o.movementMode = m
+	}
+}
+
+// WithNetwork sets the network used to resolve addresses, listen and dial, e.g. "udp" or "udp6". Defaults to
+// "udp4" since the drone only speaks IPv4: on dual-stack hosts, "udp" may bind IPv6 sockets that never receive
+// the drone's datagrams.
+func WithNetwork(network string) Option {
+	return func(o *options) {
+		o.network = network
+	}
+}
+
+// WithPreflightThresholds sets the thresholds used by PreflightCheck. Zero fields keep their default value.
+func WithPreflightThresholds(t PreflightThresholds) Option {
+	return func(o *options) {
+		o.preflightThresholds = t
+	}
+}
+
+// WithRCMaxRate caps the rate at which SetSticks sends rc cmds to hz per second. Positions set in between are
+// coalesced so that only the latest ones are sent. Since rc cmds are not acknowledged, sending them faster than
+// the drone absorbs them silently degrades the link instead of returning errors. Disabled by default.
+func WithRCMaxRate(hz int) Option {
+	return func(o *options) {
+		o.rcMaxRate = hz
+	}
+}
+
+// WithRecorder makes the drone capture cmds sent, and states and video packets received, with the provided
+// recorder
+func WithRecorder(r *Recorder) Option {
+	return func(o *options) {
+		o.recorder = r
+	}
+}
+
+// WithTelemetryLostAction sets the cmd sent when telemetry is lost while airborne. It only matters when
+// WithTelemetryTimeout is used. Nothing is sent by default.
+func WithTelemetryLostAction(a TelemetryLostAction) Option {
+	return func(o *options) {
+		o.telemetryLostAction = a
+	}
+}
+
+// WithTelemetryTimeout makes the drone dispatch the TelemetryLost event when no state has been received
+// for the provided duration while airborne. States are checked every quarter of the timeout, but not more often
+// than every 10ms. Disabled by default.
+func WithTelemetryTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.telemetryTimeout = timeout
+	}
+}
+
+// WithTimeouts sets cmd timeouts. Zero fields keep their default value.
+func WithTimeouts(t Timeouts) Option {
+	return func(o *options) {
+		o.timeouts = t
+	}
+}
+
+// WithVideoBufferSize makes the drone dispatch VideoPacket and VideoFrame events through a buffer of n events,
+// and wait for each of them to be processed before dispatching the next one. When handlers are too slow and the
+// buffer is full, events are dropped according to the policy set with WithVideoDropPolicy and counted in
+// VideoStats. Disabled by default, in which case events pile up in memory.
+func WithVideoBufferSize(n int) Option {
+	return func(o *options) {
+		o.videoBufferSize = n
+	}
+}
+
+// WithVideoDecoder makes the drone decode H264 access units with the provided decoder and dispatch the
+// resulting images through the VideoImage event. Decoding happens in a dedicated goroutine.
+func WithVideoDecoder(dec Decoder) Option {
+	return func(o *options) {
+		o.videoDecoder = dec
+	}
+}
+
+// WithVideoDropPolicy sets which events are dropped when the video buffer is full. It only matters when
+// WithVideoBufferSize is used. Defaults to dropping the oldest events.
+func WithVideoDropPolicy(p VideoDropPolicy) Option {
+	return func(o *options) {
+		o.videoDropPolicy = p
+	}
+}
+
+// WithVideoFrames makes the drone parse the video stream and dispatch complete H264 access units
+// through the VideoFrame event. Disabled by default.
+func WithVideoFrames(enabled bool) Option {
+	return func(o *options) {
+		o.videoFrames = enabled
+	}
+}
+
+// WithVideoIdleTimeout sets the duration without video datagrams after which the VideoStopped event is
+// dispatched. Defaults to 1s.
+func WithVideoIdleTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		if timeout > 0 {
+			o.videoIdleTimeout = timeout
+		}
+	}
+}
+
+// WithVideoMTU sets the size (bytes) of full video datagrams: a datagram of exactly n bytes means the packet
+// continues in the next datagram. When it doesn't match the size of the datagrams sent by the drone, which
+// depends on the firmware and the path MTU, packets are split or merged and no frame or only corrupt frames are
+// decoded. It must not be greater than the size set with WithVideoReadSize. Defaults to 1460.
+func WithVideoMTU(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.videoMTU = n
+		}
+	}
+}
+
+// WithVideoOptional makes Start log an error and continue without video instead of failing when the video
+// connection can't be created, which happens when another process, such as a previous run that didn't clean up,
+// already listens on the video port. Cmds and states keep working and StartVideo returns ErrVideoUnavailable.
+// Disabled by default.
+func WithVideoOptional(enabled bool) Option {
+	return func(o *options) {
+		o.videoOptional = enabled
+	}
+}
+
+// WithVideoPackets makes the drone dispatch raw video packets through the VideoPacket event, which is
+// convenient to pipe the stream to ffmpeg. Enabled by default.
+func WithVideoPackets(enabled bool) Option {
+	return func(o *options) {
+		o.videoPackets = enabled
+	}
+}
+
+// WithVideoReadBufferSize sets the size (bytes) of the video socket's OS read buffer. The default OS value is
+// often too small to absorb bursts of keyframe datagrams, which leads to dropped datagrams and corrupted
+// video: 1MB is a good start. The OS may cap the value.
+func WithVideoReadBufferSize(n int) Option {
+	return func(o *options) {
+		o.videoReadBufferSize = n
+	}
+}
+
+// WithVideoReadSize sets the size (bytes) of the slice each video datagram is read into. It must be greater than
+// the largest datagram, otherwise datagrams are truncated. Defaults to 2048, which fits the 1460 bytes
+// datagrams sent by the drone.
+func WithVideoReadSize(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.videoReadSize = n
+		}
+	}
+}
+
+// WithVideoStartTimeout makes StartVideo() fail with ErrTimeout when no video datagram has been received within
+// the provided duration after the drone acknowledged the streamon cmd. Disabled by default.
+func WithVideoStartTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.videoStartTimeout = timeout
+	}
+}
+
+// Timeouts represents the duration after which cmds fail when no response has been received
+// Zero fields fall back to their default value
+type Timeouts struct {
+	// Used by cmds not covered by other fields. Defaults to 5s.
+	Default time.Duration
+	// Defaults to 20s
+	Flip time.Duration
+	// Defaults to 20s
+	Land time.Duration
+	// Used by up, down, left, right, forward, back, cw, ccw, go and curve. Defaults to 1m.
+	Move time.Duration
+	// Used by "?" cmds. Defaults to Default.
+	Query time.Duration
+	// Defaults to 20s
+	TakeOff time.Duration
+}
+
+func timeoutOrDefault(t, def time.Duration) time.Duration {
+	if t > 0 {
+		return t
+	}
+	return def
+}
+
+func (t Timeouts) fallback() time.Duration {
+	return timeoutOrDefault(t.Default, defaultTimeout)
+}
+
+func (t Timeouts) flip() time.Duration {
+	return timeoutOrDefault(t.Flip, flipTimeout)
+}
+
+func (t Timeouts) land() time.Duration {
+	return timeoutOrDefault(t.Land, landTimeout)
+}
+
+func (t Timeouts) move() time.Duration {
+	return timeoutOrDefault(t.Move, moveTimeout)
+}
+
+func (t Timeouts) query() time.Duration {
+	return timeoutOrDefault(t.Query, t.fallback())
+}
+
+func (t Timeouts) takeOff() time.Duration {
+	return timeoutOrDefault(t.TakeOff, takeOffTimeout)
+}
@@ -0,0 +1,103 @@
+package astitello
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// PreflightThresholds represents the thresholds used by PreflightCheck
+// Zero fields fall back to their default value
+type PreflightThresholds struct {
+	// Defaults to 85°C
+	MaxTemperature int
+	// Defaults to 20%
+	MinBattery int
+	// Defaults to 30
+	MinWifiSNR int
+}
+
+func (t PreflightThresholds) maxTemperature() int {
+	return intOrDefault(t.MaxTemperature, 85)
+}
+
+func (t PreflightThresholds) minBattery() int {
+	return intOrDefault(t.MinBattery, 20)
+}
+
+func (t PreflightThresholds) minWifiSNR() int {
+	return intOrDefault(t.MinWifiSNR, 30)
+}
+
+func intOrDefault(i, def int) int {
+	if i > 0 {
+		return i
+	}
+	return def
+}
+
+// PreflightReport represents the result of PreflightCheck
+type PreflightReport struct {
+	Battery       int    `json:"battery"`        // The percentage of the current battery level
+	BatteryOK     bool   `json:"battery_ok"`     // Whether the battery level is above the threshold
+	SDKVersion    string `json:"sdk_version"`    // The SDK version, empty if the drone doesn't support the sdk? cmd
+	Temperature   int    `json:"temperature"`    // The highest temperature (°C)
+	TemperatureOK bool   `json:"temperature_ok"` // Whether the highest temperature is below the threshold
+	WifiOK        bool   `json:"wifi_ok"`        // Whether the Wi-Fi SNR is above the threshold
+	WifiSNR       int    `json:"wifi_snr"`       // The Wi-Fi SNR
+}
+
+// OK returns whether all checks have passed
+func (r PreflightReport) OK() bool {
+	return r.BatteryOK && r.TemperatureOK && r.WifiOK
+}
+
+// PreflightCheck queries the battery level, the Wi-Fi SNR, the temperature and the SDK version, and compares
+// them against the thresholds provided to WithPreflightThresholds. It is meant to be run before every flight:
+// an error means a query failed whereas failed checks are reported through the report's OK flags. Queries stop
+// waiting for their response when the context is done.
+func (d *Drone) PreflightCheck(ctx context.Context) (r PreflightReport, err error) {
+	// Battery
+	if err = d.queryContext(ctx, "battery?", func(resp string) (err error) {
+		r.Battery, err = parseInt(resp)
+		return
+	}); err != nil {
+		err = fmt.Errorf("astitello: querying battery failed: %w", err)
+		return
+	}
+	r.BatteryOK = r.Battery >= d.o.preflightThresholds.minBattery()
+
+	// Wifi
+	if err = d.queryContext(ctx, "wifi?", func(resp string) (err error) {
+		r.WifiSNR, err = parseFloatAsInt(resp)
+		return
+	}); err != nil {
+		err = fmt.Errorf("astitello: querying wifi failed: %w", err)
+		return
+	}
+	r.WifiOK = r.WifiSNR >= d.o.preflightThresholds.minWifiSNR()
+
+	// Temperature
+	if err = d.queryContext(ctx, "temp?", func(resp string) (err error) {
+		_, r.Temperature, err = parseTemperature(resp)
+		return
+	}); err != nil {
+		err = fmt.Errorf("astitello: querying temperature failed: %w", err)
+		return
+	}
+	r.TemperatureOK = r.Temperature <= d.o.preflightThresholds.maxTemperature()
+
+	// SDK version is not supported by all drones
+	if err = d.queryContext(ctx, "sdk?", func(resp string) (err error) {
+		r.SDKVersion, err = parseSDKVersion(resp)
+		return
+	}); err != nil {
+		var de *DroneError
+		if !errors.As(err, &de) {
+			err = fmt.Errorf("astitello: querying sdk version failed: %w", err)
+			return
+		}
+		err = nil
+	}
+	return
+}
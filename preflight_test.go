@@ -0,0 +1,63 @@
+package astitello
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestPreflightCheck(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t, WithPreflightThresholds(PreflightThresholds{MinBattery: 60}))
+	defer teardown()
+
+	// Check
+	r, err := d.PreflightCheck(context.Background())
+	if err != nil {
+		t.Error(fmt.Errorf("test: checking failed: %w", err))
+	}
+	if e := (PreflightReport{
+		Battery:       50,
+		SDKVersion:    "20",
+		Temperature:   15,
+		TemperatureOK: true,
+		WifiSNR:       100,
+		WifiOK:        true,
+	}); !reflect.DeepEqual(e, r) {
+		t.Errorf("expected %+v, got %+v", e, r)
+	}
+	if r.OK() {
+		t.Error("expected ok == false, got true")
+	}
+
+	// SDK version is not supported
+	c.mt.Lock()
+	h := c.h
+	c.h = func(cmd []byte) []byte {
+		if string(cmd) == "sdk?" {
+			return []byte("unknown command: sdk?")
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+	if r, err = d.PreflightCheck(context.Background()); err != nil {
+		t.Error(fmt.Errorf("test: checking failed: %w", err))
+	} else if r.SDKVersion != "" {
+		t.Errorf("expected empty sdk version, got %s", r.SDKVersion)
+	}
+
+	// Context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	n := len(c.received())
+	if _, err = d.PreflightCheck(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %s, got %v", context.Canceled, err)
+	}
+
+	// No query should have been sent
+	if cmds := c.received(); len(cmds) > n {
+		t.Errorf("expected no cmds, got %+v", cmds[n:])
+	}
+}
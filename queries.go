@@ -0,0 +1,312 @@
+package astitello
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// query sends a query cmd and parses its response with the provided handler
+func (d *Drone) query(c string, h respHandler) (err error) {
+	return d.queryContext(context.Background(), c, h)
+}
+
+// queryContext is the same as query but stops waiting for the response when the context is done
+func (d *Drone) queryContext(ctx context.Context, c string, h respHandler) (err error) {
+	if err = d.sendCmd(&cmd{
+		cmd:     c,
+		ctx:     ctx,
+		h:       h,
+		timeout: d.o.timeouts.query(),
+	}); err != nil {
+		err = fmt.Errorf("astitello: sending %s cmd failed: %w", c, err)
+		return
+	}
+	return
+}
+
+// Battery returns the percentage of the current battery level
+func (d *Drone) Battery() (x int, err error) {
+	// It returns "87"
+	err = d.query("battery?", func(resp string) (err error) {
+		x, err = parseInt(resp)
+		return
+	})
+	return
+}
+
+// Height returns the height (cm)
+func (d *Drone) Height() (x int, err error) {
+	// It returns "10dm"
+	err = d.query("height?", func(resp string) (err error) {
+		x, err = parseHeight(resp)
+		return
+	})
+	return
+}
+
+// ToF returns the distance measured by the ToF sensor (cm)
+func (d *Drone) ToF() (x int, err error) {
+	// It returns "100mm"
+	err = d.query("tof?", func(resp string) (err error) {
+		x, err = parseToF(resp)
+		return
+	})
+	return
+}
+
+// Barometer returns the barometer measurement, in the same unit as State.Barometer
+func (d *Drone) Barometer() (x float64, err error) {
+	// It returns "-64.105316"
+	err = d.query("baro?", func(resp string) (err error) {
+		x, err = parseFloat(resp)
+		return
+	})
+	return
+}
+
+// Temperature returns the lowest and highest temperatures (°C)
+func (d *Drone) Temperature() (low, high int, err error) {
+	// It returns "14~15C"
+	err = d.query("temp?", func(resp string) (err error) {
+		low, high, err = parseTemperature(resp)
+		return
+	})
+	return
+}
+
+// Attitude returns the attitude
+func (d *Drone) Attitude() (a Attitude, err error) {
+	// It returns "pitch:0;roll:0;yaw:0;"
+	err = d.query("attitude?", func(resp string) (err error) {
+		a, err = parseAttitude(resp)
+		return
+	})
+	return
+}
+
+// Acceleration returns the acceleration
+func (d *Drone) Acceleration() (a Acceleration, err error) {
+	// It returns "agx:-3.00;agy:0.00;agz:-998.00;"
+	err = d.query("acceleration?", func(resp string) (err error) {
+		a, err = parseAcceleration(resp)
+		return
+	})
+	return
+}
+
+// SDKVersion returns the SDK version, e.g. "20" for SDK 2.0
+// Drones running SDK 1.3 don't support this cmd and respond with an error.
+func (d *Drone) SDKVersion() (v string, err error) {
+	// It returns "20"
+	err = d.query("sdk?", func(resp string) (err error) {
+		v, err = parseSDKVersion(resp)
+		return
+	})
+	return
+}
+
+func parseSDKVersion(i string) (v string, err error) {
+	// Get string
+	if v, err = parseString(i); err != nil {
+		return
+	}
+
+	// Versions are numbers, anything else is an error
+	if _, err = strconv.Atoi(v); err != nil {
+		v = ""
+		err = fmt.Errorf("astitello: invalid response: %w", &DroneError{Raw: i})
+		return
+	}
+	return
+}
+
+// SerialNumber returns the drone's serial number, e.g. "0TQDG2KEDB4F7X"
+func (d *Drone) SerialNumber() (sn string, err error) {
+	// It returns "0TQDG2KEDB4F7X"
+	err = d.query("sn?", func(resp string) (err error) {
+		sn, err = parseSerialNumber(resp)
+		return
+	})
+	return
+}
+
+func parseSerialNumber(i string) (sn string, err error) {
+	// Get string
+	if sn, err = parseString(i); err != nil {
+		return
+	}
+
+	// Serial numbers are alphanumeric
+	for _, r := range sn {
+		if (r < '0' || r > '9') && (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') {
+			sn = ""
+			err = fmt.Errorf("astitello: invalid response: %w", &DroneError{Raw: i})
+			return
+		}
+	}
+	return
+}
+
+// parseUnit parses a number followed by one of the provided units and returns it multiplied by the unit's
+// factor and rounded. A missing unit is allowed and has a factor of 1.
+func parseUnit(i string, units map[string]float64) (x int, err error) {
+	// Get factor
+	f := 1.0
+	for u, uf := range units {
+		if strings.HasSuffix(i, u) {
+			i = strings.TrimSpace(strings.TrimSuffix(i, u))
+			f = uf
+			break
+		}
+	}
+
+	// Parse
+	var v float64
+	if v, err = parseFloat(i); err != nil {
+		return
+	}
+	x = int(math.Round(v * f))
+	return
+}
+
+// parseFloat parses a float, ignoring surrounding spaces and any non-numeric trailing characters since
+// firmwares don't all format numbers the same way
+func parseFloat(i string) (f float64, err error) {
+	v := strings.TrimRightFunc(strings.TrimSpace(i), func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if f, err = strconv.ParseFloat(v, 64); err != nil {
+		err = fmt.Errorf("astitello: parsing float %s failed: %w", i, err)
+		return
+	}
+	return
+}
+
+func parseHeight(i string) (int, error) {
+	return parseUnit(i, map[string]float64{"cm": 1, "dm": 10})
+}
+
+func parseToF(i string) (int, error) {
+	return parseUnit(i, map[string]float64{"cm": 1, "mm": 0.1})
+}
+
+// parseInt parses an integer, ignoring surrounding spaces and any non-numeric trailing characters
+func parseInt(i string) (x int, err error) {
+	v := strings.TrimRightFunc(strings.TrimSpace(i), func(r rune) bool { return r < '0' || r > '9' })
+	if x, err = strconv.Atoi(v); err != nil {
+		err = fmt.Errorf("astitello: parsing int %s failed: %w", i, err)
+		return
+	}
+	return
+}
+
+// parseFloatAsInt parses a float and rounds it, since some firmwares respond "100.0" where others
+// respond "100"
+func parseFloatAsInt(i string) (x int, err error) {
+	var f float64
+	if f, err = parseFloat(i); err != nil {
+		return
+	}
+	x = int(math.Round(f))
+	return
+}
+
+// parseString parses a single word, anything else being an error such as "unknown command: sn?"
+func parseString(i string) (s string, err error) {
+	if s = strings.TrimSpace(i); s == "" || s == "ok" || strings.ContainsAny(s, " \t:") {
+		s = ""
+		err = fmt.Errorf("astitello: invalid response: %w", &DroneError{Raw: i})
+		return
+	}
+	return
+}
+
+// parseKeyValues parses "key1:value1;key2:value2;" responses, which may contain spaces and may not end with ";"
+func parseKeyValues(i string, keys ...string) (vs map[string]string, err error) {
+	// Loop through pairs
+	vs = make(map[string]string)
+	for _, p := range strings.Split(strings.TrimSpace(i), ";") {
+		// Split
+		ps := strings.SplitN(p, ":", 2)
+		if len(ps) != 2 {
+			continue
+		}
+		vs[strings.TrimSpace(ps[0])] = strings.TrimSpace(ps[1])
+	}
+
+	// Make sure keys are present
+	for _, k := range keys {
+		if _, ok := vs[k]; !ok {
+			err = fmt.Errorf("astitello: key %s not found in %s", k, i)
+			return
+		}
+	}
+	return
+}
+
+func parseTemperature(i string) (low, high int, err error) {
+	// Split
+	ps := strings.Split(strings.TrimSuffix(strings.TrimSpace(i), "C"), "~")
+	if len(ps) != 2 {
+		err = fmt.Errorf("astitello: invalid temperature %s", i)
+		return
+	}
+
+	// Parse
+	if low, err = parseInt(ps[0]); err != nil {
+		return
+	}
+	if high, err = parseInt(ps[1]); err != nil {
+		return
+	}
+	return
+}
+
+func parseAttitude(i string) (a Attitude, err error) {
+	// Parse key values
+	var vs map[string]string
+	if vs, err = parseKeyValues(i, "pitch", "roll", "yaw"); err != nil {
+		return
+	}
+
+	// Parse values
+	for _, v := range []struct {
+		k string
+		x *int
+	}{
+		{k: "pitch", x: &a.Pitch},
+		{k: "roll", x: &a.Roll},
+		{k: "yaw", x: &a.Yaw},
+	} {
+		if *v.x, err = strconv.Atoi(vs[v.k]); err != nil {
+			err = fmt.Errorf("astitello: parsing %s failed: %w", v.k, err)
+			return
+		}
+	}
+	return
+}
+
+func parseAcceleration(i string) (a Acceleration, err error) {
+	// Parse key values
+	var vs map[string]string
+	if vs, err = parseKeyValues(i, "agx", "agy", "agz"); err != nil {
+		return
+	}
+
+	// Parse values
+	for _, v := range []struct {
+		k string
+		x *float64
+	}{
+		{k: "agx", x: &a.X},
+		{k: "agy", x: &a.Y},
+		{k: "agz", x: &a.Z},
+	} {
+		if *v.x, err = strconv.ParseFloat(vs[v.k], 64); err != nil {
+			err = fmt.Errorf("astitello: parsing %s failed: %w", v.k, err)
+			return
+		}
+	}
+	return
+}
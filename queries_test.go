@@ -0,0 +1,374 @@
+package astitello
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseQueries(t *testing.T) {
+	// Units
+	for _, v := range []struct {
+		e   int
+		err bool
+		fn  func(string) (int, error)
+		i   string
+	}{
+		{fn: parseHeight, i: "10dm", e: 100},
+		{fn: parseHeight, i: "0dm", e: 0},
+		{fn: parseHeight, i: "17cm", e: 17},
+		{fn: parseHeight, i: "17", e: 17},
+		{fn: parseHeight, i: "dm", err: true},
+		{fn: parseToF, i: "100mm", e: 10},
+		{fn: parseToF, i: "105mm", e: 11},
+		{fn: parseToF, i: "12cm", e: 12},
+		{fn: parseToF, i: "invalid", err: true},
+	} {
+		g, err := v.fn(v.i)
+		if v.err {
+			if err == nil {
+				t.Errorf("%s: expected error", v.i)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("%s: expected no error, got %s", v.i, err)
+		}
+		if g != v.e {
+			t.Errorf("%s: expected %d, got %d", v.i, v.e, g)
+		}
+	}
+
+	// Ints
+	for _, v := range []struct {
+		e   int
+		err bool
+		fn  func(string) (int, error)
+		i   string
+	}{
+		{fn: parseInt, i: "87", e: 87},
+		{fn: parseInt, i: "87\r\n", e: 87},
+		{fn: parseInt, i: " 87 ", e: 87},
+		{fn: parseInt, i: "87%", e: 87},
+		{fn: parseInt, i: "-2", e: -2},
+		{fn: parseInt, i: "ok", err: true},
+		{fn: parseInt, i: "error", err: true},
+		{fn: parseFloatAsInt, i: "100.0", e: 100},
+		{fn: parseFloatAsInt, i: "90.5", e: 91},
+		{fn: parseFloatAsInt, i: "90.4\r\n", e: 90},
+		{fn: parseFloatAsInt, i: "100", e: 100},
+		{fn: parseFloatAsInt, i: "error", err: true},
+	} {
+		g, err := v.fn(v.i)
+		if v.err {
+			if err == nil {
+				t.Errorf("%q: expected error", v.i)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("%q: expected no error, got %s", v.i, err)
+		}
+		if g != v.e {
+			t.Errorf("%q: expected %d, got %d", v.i, v.e, g)
+		}
+	}
+
+	// Strings
+	for _, v := range []struct {
+		e   string
+		err bool
+		i   string
+	}{
+		{i: "20", e: "20"},
+		{i: "0TQDG2KEDB4F7X\r\n", e: "0TQDG2KEDB4F7X"},
+		{i: "", err: true},
+		{i: "ok", err: true},
+		{i: "unknown command: sdk?", err: true},
+	} {
+		g, err := parseString(v.i)
+		if v.err {
+			if err == nil {
+				t.Errorf("%q: expected error", v.i)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("%q: expected no error, got %s", v.i, err)
+		}
+		if g != v.e {
+			t.Errorf("%q: expected %s, got %s", v.i, v.e, g)
+		}
+	}
+
+	// Floats
+	for _, v := range []struct {
+		e   float64
+		err bool
+		i   string
+	}{
+		{i: "100", e: 100},
+		{i: "100.0", e: 100},
+		{i: "90.5", e: 90.5},
+		{i: "100 ", e: 100},
+		{i: " 100\r\n", e: 100},
+		{i: "90dBm", e: 90},
+		{i: "-64.105316", e: -64.105316},
+		{i: "ok", err: true},
+	} {
+		g, err := parseFloat(v.i)
+		if v.err {
+			if err == nil {
+				t.Errorf("%q: expected error", v.i)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("%q: expected no error, got %s", v.i, err)
+		}
+		if g != v.e {
+			t.Errorf("%q: expected %f, got %f", v.i, v.e, g)
+		}
+	}
+
+	// Temperature
+	for _, v := range []struct {
+		err       bool
+		high, low int
+		i         string
+	}{
+		{i: "14~15C", low: 14, high: 15},
+		{i: "83~86C", low: 83, high: 86},
+		{i: "14~15", low: 14, high: 15},
+		{i: "15C", err: true},
+		{i: "a~15C", err: true},
+	} {
+		low, high, err := parseTemperature(v.i)
+		if v.err {
+			if err == nil {
+				t.Errorf("%s: expected error", v.i)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("%s: expected no error, got %s", v.i, err)
+		}
+		if low != v.low || high != v.high {
+			t.Errorf("%s: expected %d~%d, got %d~%d", v.i, v.low, v.high, low, high)
+		}
+	}
+
+	// Attitude
+	a, err := parseAttitude("pitch:1;roll:-2;yaw:3;")
+	if err != nil {
+		t.Error(fmt.Errorf("test: parsing attitude failed: %w", err))
+	}
+	if e := (Attitude{Pitch: 1, Roll: -2, Yaw: 3}); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected %+v, got %+v", e, a)
+	}
+	for _, i := range []string{
+		"pitch:1;roll:-2;yaw:3",
+		"pitch: 1; roll: -2; yaw: 3;\r\n",
+		"yaw:3;pitch:1;roll:-2;",
+	} {
+		if a, err = parseAttitude(i); err != nil {
+			t.Errorf("%q: expected no error, got %s", i, err)
+		}
+		if e := (Attitude{Pitch: 1, Roll: -2, Yaw: 3}); !reflect.DeepEqual(e, a) {
+			t.Errorf("%q: expected %+v, got %+v", i, e, a)
+		}
+	}
+	for _, i := range []string{"pitch:1;roll:-2;", "pitch:1;roll:-2;yaw:a;", "error"} {
+		if _, err = parseAttitude(i); err == nil {
+			t.Errorf("%q: expected error", i)
+		}
+	}
+
+	// Acceleration
+	ac, err := parseAcceleration("agx:-3.00;agy:0.00;agz:-998.00;")
+	if err != nil {
+		t.Error(fmt.Errorf("test: parsing acceleration failed: %w", err))
+	}
+	if e := (Acceleration{X: -3, Y: 0, Z: -998}); !reflect.DeepEqual(e, ac) {
+		t.Errorf("expected %+v, got %+v", e, ac)
+	}
+	if ac, err = parseAcceleration("agx: -3.00; agy: 0.00; agz: -998.00\r\n"); err != nil {
+		t.Error(fmt.Errorf("test: parsing acceleration failed: %w", err))
+	}
+	if e := (Acceleration{X: -3, Y: 0, Z: -998}); !reflect.DeepEqual(e, ac) {
+		t.Errorf("expected %+v, got %+v", e, ac)
+	}
+	if _, err = parseAcceleration("agx:a;"); err == nil {
+		t.Error("expected error")
+	}
+
+	// SDK version
+	var de *DroneError
+	if _, err = parseSDKVersion("unknown command: sdk?"); !errors.As(err, &de) {
+		t.Errorf("expected DroneError, got %v", err)
+	}
+
+	// Serial number
+	for _, i := range []string{"", "ok", "unknown command: sn?"} {
+		if _, err = parseSerialNumber(i); !errors.As(err, &de) {
+			t.Errorf("%s: expected DroneError, got %v", i, err)
+		}
+	}
+}
+
+func TestQueries(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Battery
+	b, err := d.Battery()
+	if err != nil {
+		t.Error(fmt.Errorf("test: querying battery failed: %w", err))
+	}
+	if e := 50; b != e {
+		t.Errorf("expected %d, got %d", e, b)
+	}
+
+	// Height
+	h, err := d.Height()
+	if err != nil {
+		t.Error(fmt.Errorf("test: querying height failed: %w", err))
+	}
+	if e := 100; h != e {
+		t.Errorf("expected %d, got %d", e, h)
+	}
+
+	// ToF
+	tof, err := d.ToF()
+	if err != nil {
+		t.Error(fmt.Errorf("test: querying tof failed: %w", err))
+	}
+	if e := 11; tof != e {
+		t.Errorf("expected %d, got %d", e, tof)
+	}
+
+	// Barometer
+	baro, err := d.Barometer()
+	if err != nil {
+		t.Error(fmt.Errorf("test: querying barometer failed: %w", err))
+	}
+	if e := -64.105316; baro != e {
+		t.Errorf("expected %f, got %f", e, baro)
+	}
+
+	// Temperature
+	low, high, err := d.Temperature()
+	if err != nil {
+		t.Error(fmt.Errorf("test: querying temperature failed: %w", err))
+	}
+	if low != 14 || high != 15 {
+		t.Errorf("expected 14~15, got %d~%d", low, high)
+	}
+
+	// Attitude
+	a, err := d.Attitude()
+	if err != nil {
+		t.Error(fmt.Errorf("test: querying attitude failed: %w", err))
+	}
+	if e := (Attitude{Pitch: 1, Roll: -2, Yaw: 3}); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected %+v, got %+v", e, a)
+	}
+
+	// Acceleration
+	ac, err := d.Acceleration()
+	if err != nil {
+		t.Error(fmt.Errorf("test: querying acceleration failed: %w", err))
+	}
+	if e := (Acceleration{X: -3, Y: 0, Z: -998}); !reflect.DeepEqual(e, ac) {
+		t.Errorf("expected %+v, got %+v", e, ac)
+	}
+
+	// SDK version
+	v, err := d.SDKVersion()
+	if err != nil {
+		t.Error(fmt.Errorf("test: querying sdk version failed: %w", err))
+	}
+	if e := "20"; v != e {
+		t.Errorf("expected %s, got %s", e, v)
+	}
+
+	// Serial number
+	if v, err = d.SerialNumber(); err != nil {
+		t.Error(fmt.Errorf("test: querying serial number failed: %w", err))
+	}
+	if e := "0TQDG2KEDB4F7X"; v != e {
+		t.Errorf("expected %s, got %s", e, v)
+	}
+
+	// Make wifi? and speed? responses less strict
+	c.mt.Lock()
+	ch := c.h
+	c.h = func(cmd []byte) []byte {
+		switch string(cmd) {
+		case "speed?":
+			return []byte("100 ")
+		case "wifi?":
+			return []byte("90.5")
+		}
+		return ch(cmd)
+	}
+	c.mt.Unlock()
+
+	// Wifi
+	snr, err := d.Wifi()
+	if err != nil {
+		t.Error(fmt.Errorf("test: querying wifi failed: %w", err))
+	}
+	if e := 91; snr != e {
+		t.Errorf("expected %d, got %d", e, snr)
+	}
+
+	// Speed
+	speed, err := d.Speed()
+	if err != nil {
+		t.Error(fmt.Errorf("test: querying speed failed: %w", err))
+	}
+	if e := 100; speed != e {
+		t.Errorf("expected %d, got %d", e, speed)
+	}
+}
+
+func TestPing(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t, WithTimeouts(Timeouts{Query: 20 * time.Millisecond}))
+	defer teardown()
+
+	// Delay responses
+	c.mt.Lock()
+	h := c.h
+	c.h = func(cmd []byte) []byte {
+		if string(cmd) == "wifi?" {
+			time.Sleep(5 * time.Millisecond)
+		}
+		return h(cmd)
+	}
+	c.mt.Unlock()
+
+	// Ping
+	rtt, err := d.Ping(context.Background())
+	if err != nil {
+		t.Error(fmt.Errorf("test: pinging failed: %w", err))
+	}
+	if e := 5 * time.Millisecond; rtt < e {
+		t.Errorf("expected rtt >= %s, got %s", e, rtt)
+	}
+
+	// Context is canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = d.Ping(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %s, got %v", context.Canceled, err)
+	}
+
+	// Make the drone stop responding
+	c.mt.Lock()
+	c.timeout = true
+	c.mt.Unlock()
+	if _, err = d.Ping(context.Background()); !errors.Is(err, ErrTimeout) {
+		t.Errorf("expected %s, got %v", ErrTimeout, err)
+	}
+}
@@ -0,0 +1,240 @@
+package astitello
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Interval at which the RC loop re-sends sticks positions
+var rcLoopInterval = 50 * time.Millisecond
+
+// RCController represents an object re-sending the latest sticks positions at a fixed interval, which
+// makes flight smooth and keeps the drone alive
+type RCController struct {
+	cancel context.CancelFunc
+	ctx    context.Context
+	d      *Drone
+	done   chan struct{}
+	g      Gamepad
+	gm     GamepadMapping
+	m      *sync.Mutex // Locks g, gm and sticks
+	o      *sync.Once  // Limits Stop()
+	sticks [4]int
+}
+
+// StartRCLoop starts re-sending sticks positions in a goroutine until the controller is stopped or the drone
+// is closed. If the drone is not started, the returned controller is already stopped.
+func (d *Drone) StartRCLoop() *RCController {
+	// Create controller
+	c := &RCController{
+		d:    d,
+		done: make(chan struct{}),
+		m:    &sync.Mutex{},
+		o:    &sync.Once{},
+	}
+
+	// Create context
+	if ctx := d.sessionContext(); ctx != nil {
+		c.ctx, c.cancel = context.WithCancel(ctx)
+	} else {
+		c.ctx, c.cancel = context.WithCancel(context.Background())
+		c.cancel()
+	}
+
+	// Loop
+	go c.loop()
+	return c
+}
+
+// SetAxes updates the sticks positions that will be sent next
+// Check out SetSticks for more details about the values
+func (c *RCController) SetAxes(lr, fb, ud, y int) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.sticks = [4]int{lr, fb, ud, y}
+}
+
+// SetGamepad makes the loop poll the gamepad for sticks positions instead of using the ones provided to
+// SetAxes. Provide a nil gamepad to stop polling it.
+func (c *RCController) SetGamepad(g Gamepad, m GamepadMapping) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.g = g
+	c.gm = m
+}
+
+func (c *RCController) loop() {
+	// Make sure to signal the loop is done
+	defer close(c.done)
+
+	// Create ticker
+	t := time.NewTicker(rcLoopInterval)
+	defer t.Stop()
+
+	// Loop
+	for {
+		select {
+		case <-t.C:
+			// Get sticks
+			c.m.Lock()
+			s := c.sticks
+			if c.g != nil {
+				s[0], s[1], s[2], s[3] = c.gm.Map(c.g.Axes())
+			}
+			c.m.Unlock()
+
+			// Set sticks
+			if err := c.d.SetSticks(s[0], s[1], s[2], s[3]); err != nil && c.ctx.Err() == nil {
+				c.d.l.Error(fmt.Errorf("astitello: setting sticks failed: %w", err))
+			}
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+// Stop stops the loop and sends neutral sticks positions
+func (c *RCController) Stop() (err error) {
+	c.o.Do(func() {
+		// Stop loop
+		c.cancel()
+
+		// Wait for loop to be done so that no other positions are sent afterwards
+		<-c.done
+
+		// Set neutral sticks
+		if err = c.d.Hover(); err != nil {
+			err = fmt.Errorf("astitello: setting neutral sticks failed: %w", err)
+			return
+		}
+	})
+	return
+}
+
+// SetSticksFor sets the sticks positions for the duration, e.g. to move forward for 2 seconds, and then sets them
+// back to their neutral position, including when the context is done early. Positions are re-sent by an RC loop
+// in the meantime so that the drone keeps applying them. Check out SetSticks for more details about the values.
+func (d *Drone) SetSticksFor(ctx context.Context, lr, fb, ud, y int, dur time.Duration) (err error) {
+	// Set sticks right away rather than waiting for the loop's first tick
+	if err = d.SetSticks(lr, fb, ud, y); err != nil {
+		err = fmt.Errorf("astitello: setting sticks failed: %w", err)
+		return
+	}
+
+	// Start loop
+	c := d.StartRCLoop()
+	c.SetAxes(lr, fb, ud, y)
+
+	// Wait
+	t := time.NewTimer(dur)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+		err = fmt.Errorf("astitello: waiting for duration failed: %w", ctx.Err())
+	}
+
+	// Stop loop, which sets neutral sticks
+	if serr := c.Stop(); serr != nil && err == nil {
+		err = fmt.Errorf("astitello: stopping rc loop failed: %w", serr)
+	}
+	return
+}
+
+// HeightRange represents the range of heights reported by states over a period of time
+type HeightRange struct {
+	Max     int `json:"max"`     // cm
+	Min     int `json:"min"`     // cm
+	Samples int `json:"samples"` // The number of states received, Max and Min are meaningless when 0
+}
+
+// HoverFor keeps sticks in their neutral position for the duration, which makes the drone hold its position,
+// and returns the range of heights reported by states in the meantime, which is a simple way to check how
+// stable the drone is. Check out SetSticksFor for more details.
+func (d *Drone) HoverFor(ctx context.Context, dur time.Duration) (r HeightRange, err error) {
+	// Create context
+	hctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Handle states
+	var hr HeightRange
+	m := &sync.Mutex{} // Locks hr
+	d.onUntil(hctx, StateEvent, StateEventHandler(func(s State) {
+		// Lock
+		m.Lock()
+		defer m.Unlock()
+
+		// Update range
+		if hr.Samples == 0 || s.Height > hr.Max {
+			hr.Max = s.Height
+		}
+		if hr.Samples == 0 || s.Height < hr.Min {
+			hr.Min = s.Height
+		}
+		hr.Samples++
+	}))
+
+	// Hover
+	err = d.SetSticksFor(ctx, 0, 0, 0, 0, dur)
+
+	// Stop handling states
+	cancel()
+
+	// Copy range
+	m.Lock()
+	r = hr
+	m.Unlock()
+	return
+}
+
+// coalesceSticks replaces the sticks positions waiting to be sent and makes sure a goroutine sends them, at
+// most once per WithRCMaxRate interval
+func (d *Drone) coalesceSticks(s [4]int) {
+	// Lock
+	d.mrc.Lock()
+	defer d.mrc.Unlock()
+
+	// Update sticks
+	d.rcSticks = &s
+
+	// Already sending
+	if d.rcSending {
+		return
+	}
+	d.rcSending = true
+
+	// Send
+	go d.sendCoalescedSticks(d.sessionContext())
+}
+
+func (d *Drone) sendCoalescedSticks(ctx context.Context) {
+	// Loop
+	interval := time.Second / time.Duration(d.o.rcMaxRate)
+	for {
+		// Get sticks
+		d.mrc.Lock()
+		s := d.rcSticks
+		d.rcSticks = nil
+
+		// Nothing left to send. State is reset while locked so that new sticks can't be missed.
+		if s == nil || ctx.Err() != nil {
+			d.rcSending = false
+			d.mrc.Unlock()
+			return
+		}
+		d.mrc.Unlock()
+
+		// Send sticks
+		if err := d.sendSticks(s[0], s[1], s[2], s[3]); err != nil && ctx.Err() == nil {
+			d.l.Error(fmt.Errorf("astitello: setting sticks failed: %w", err))
+		}
+
+		// Wait
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+		}
+	}
+}
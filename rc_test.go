@@ -0,0 +1,184 @@
+package astitello
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRCController(t *testing.T) {
+	// Update defaults
+	i := rcLoopInterval
+	rcLoopInterval = 5 * time.Millisecond
+	defer func() { rcLoopInterval = i }()
+
+	// Loop is stopped when the drone is not started
+	rc := New(nil).StartRCLoop()
+	select {
+	case <-rc.done:
+	case <-time.After(time.Second):
+		t.Error("expected rc loop to be stopped")
+	}
+	if err := rc.Stop(); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("expected %s, got %v", ErrNotConnected, err)
+	}
+
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Start loop
+	rc = d.StartRCLoop()
+	rc.SetAxes(1, 2, 3, 4)
+
+	// Wait for sticks positions to be sent several times
+	for n := 0; n < 2; {
+		n = 0
+		for _, cmd := range c.received() {
+			if cmd == "rc 1 2 3 4" {
+				n++
+			}
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Stop loop
+	if err := rc.Stop(); err != nil {
+		t.Error(fmt.Errorf("test: stopping rc loop failed: %w", err))
+	}
+
+	// Last cmd should be neutral sticks positions
+	time.Sleep(20 * time.Millisecond)
+	cmds := c.received()
+	if e, g := "rc 0 0 0 0", cmds[len(cmds)-1]; e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+}
+
+func TestSetSticksFor(t *testing.T) {
+	// Update defaults
+	i := rcLoopInterval
+	rcLoopInterval = 5 * time.Millisecond
+	defer func() { rcLoopInterval = i }()
+
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Wait for the last cmd received to be neutral sticks positions, since rc cmds have no response
+	lastNeutral := func() (cmds []string) {
+		for n := time.Now(); time.Since(n) < time.Second; time.Sleep(time.Millisecond) {
+			if cmds = c.received(); cmds[len(cmds)-1] == "rc 0 0 0 0" {
+				return
+			}
+		}
+		t.Errorf("expected rc 0 0 0 0, got %+v", cmds)
+		return
+	}
+
+	// Sticks should be kept alive for the duration
+	if err := d.SetSticksFor(context.Background(), 1, 2, 3, 4, 50*time.Millisecond); err != nil {
+		t.Error(fmt.Errorf("test: setting sticks failed: %w", err))
+	}
+	cmds := lastNeutral()
+	n := 0
+	for _, cmd := range cmds {
+		if cmd == "rc 1 2 3 4" {
+			n++
+		}
+	}
+	if n < 2 {
+		t.Errorf("expected sticks to be sent several times, got %+v", cmds)
+	}
+
+	// Sticks should be neutral when the context is done early
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := d.SetSticksFor(ctx, 5, 6, 7, 8, time.Hour); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %s", err)
+	}
+	lastNeutral()
+}
+
+func TestHoverFor(t *testing.T) {
+	// Update defaults
+	i := rcLoopInterval
+	rcLoopInterval = 5 * time.Millisecond
+	defer func() { rcLoopInterval = i }()
+
+	// Set up and start
+	d, c, s, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Send states while hovering
+	go func() {
+		for idx := 0; idx < 3; idx++ {
+			time.Sleep(10 * time.Millisecond)
+			s.conn.Write([]byte(strState))
+		}
+	}()
+
+	// Hover
+	n := time.Now()
+	r, err := d.HoverFor(context.Background(), 100*time.Millisecond)
+	if err != nil {
+		t.Error(fmt.Errorf("test: hovering failed: %w", err))
+	}
+
+	// It should return after the duration
+	if e, g := 100*time.Millisecond, time.Since(n); g < e {
+		t.Errorf("expected at least %s, got %s", e, g)
+	}
+
+	// Heights should have been tracked
+	if r.Samples == 0 || r.Min != 17 || r.Max != 17 {
+		t.Errorf("invalid height range %+v", r)
+	}
+
+	// Sticks should have been neutral throughout
+	for _, cmd := range c.received() {
+		if strings.HasPrefix(cmd, "rc ") && cmd != "rc 0 0 0 0" {
+			t.Errorf("expected neutral sticks, got %s", cmd)
+		}
+	}
+}
+
+func TestRCMaxRate(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t, WithRCMaxRate(20))
+	defer teardown()
+
+	// Flood
+	n := time.Now()
+	for i := 0; time.Since(n) < 200*time.Millisecond; i++ {
+		if err := d.SetSticks(i%100, 0, 0, 0); err != nil {
+			t.Fatal(fmt.Errorf("test: setting sticks failed: %w", err))
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := d.SetSticks(1, 2, 3, 4); err != nil {
+		t.Fatal(fmt.Errorf("test: setting sticks failed: %w", err))
+	}
+
+	// Wait for the latest sticks to be sent
+	time.Sleep(100 * time.Millisecond)
+
+	// Rate should be capped to one cmd every 50ms
+	var rcs []string
+	for _, cmd := range c.received() {
+		if strings.HasPrefix(cmd, "rc ") {
+			rcs = append(rcs, cmd)
+		}
+	}
+	if len(rcs) < 2 || len(rcs) > 7 {
+		t.Fatalf("expected between 2 and 7 rc cmds, got %d", len(rcs))
+	}
+
+	// Latest sticks should be sent last
+	if e, g := "rc 1 2 3 4", rcs[len(rcs)-1]; e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+}
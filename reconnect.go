@@ -0,0 +1,128 @@
+package astitello
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// Number of consecutive read errors after which a connection is re-created
+var reconnectErrorThreshold = 3
+
+func (d *Drone) shouldReconnect(errs int) bool {
+	return d.o.reconnectMaxAttempts > 0 && errs >= reconnectErrorThreshold
+}
+
+// Reconnect closes the cmd connection and dials addr instead, without re-creating the state and video
+// connections. It is needed to keep controlling the drone once it has joined an access point through
+// ConnectToAP() since it then gets a new address on the LAN. Unless WithManualHandshake is used, the "command"
+// handshake is sent again. The new address is used by auto-reconnection as well, including when dialing it fails
+// here, and by later calls to Start().
+func (d *Drone) Reconnect(addr string) (err error) {
+	// Not connected
+	if !d.Connected() {
+		return ErrNotConnected
+	}
+
+	// Lock
+	d.mco.Lock()
+
+	// Close previous connection so that its port can be reused
+	d.cmdConn.Close()
+	d.raddr = addr
+
+	// Dial
+	var conn *net.UDPConn
+	if conn, err = d.dialCmdAddr(addr); err != nil {
+		d.mco.Unlock()
+		err = fmt.Errorf("astitello: dialing %s failed: %w", addr, err)
+		return
+	}
+
+	// Update connection
+	d.cmdConn = conn
+	d.mco.Unlock()
+
+	// Late responses can't be received on the new connection
+	d.removeAbandonedCmds()
+
+	// Dispatch
+	d.e.Dispatch(ReconnectedEvent, nil)
+
+	// Command
+	if !d.o.manualHandshake {
+		if err = d.EnterSDKMode(); err != nil {
+			err = fmt.Errorf("astitello: entering sdk mode failed: %w", err)
+			return
+		}
+	}
+	return
+}
+
+// replacedCmdConn returns the cmd connection if it is not conn anymore, which happens after Reconnect()
+func (d *Drone) replacedCmdConn(conn *net.UDPConn) *net.UDPConn {
+	d.mco.Lock()
+	defer d.mco.Unlock()
+	if d.cmdConn != nil && d.cmdConn != conn {
+		return d.cmdConn
+	}
+	return nil
+}
+
+// reconnect re-creates a connection with backoff and returns it once it has replaced the previous one
+func (d *Drone) reconnect(c **net.UDPConn, fn func() (*net.UDPConn, error)) (conn *net.UDPConn, err error) {
+	// Dispatch
+	d.e.Dispatch(ReconnectingEvent, nil)
+
+	// Close previous connection so that its port can be reused
+	d.mco.Lock()
+	(*c).Close()
+	d.mco.Unlock()
+
+	// Loop through attempts
+	for attempt := 1; ; attempt++ {
+		// Wait
+		select {
+		case <-time.After(time.Duration(attempt) * d.o.reconnectBackoff):
+		case <-d.ctx.Done():
+			err = d.ctx.Err()
+			return
+		}
+
+		// Create connection
+		if conn, err = fn(); err == nil {
+			break
+		}
+
+		// Max attempts has been reached
+		if attempt >= d.o.reconnectMaxAttempts {
+			err = fmt.Errorf("astitello: max attempts reached: %w", err)
+			return
+		}
+
+		// Log
+		d.l.Error(fmt.Errorf("astitello: reconnection attempt %d failed: %w", attempt, err))
+	}
+
+	// Lock
+	d.mco.Lock()
+	defer d.mco.Unlock()
+
+	// Drone has been closed in the meantime
+	if err = d.ctx.Err(); err != nil {
+		conn.Close()
+		return
+	}
+
+	// Update connection
+	*c = conn
+
+	// Late responses can't be received on the new connection
+	if c == &d.cmdConn {
+		d.removeAbandonedCmds()
+	}
+
+	// Dispatch
+	d.e.Dispatch(ReconnectedEvent, nil)
+	return
+}
@@ -0,0 +1,182 @@
+package astitello
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"sync"
+	"time"
+)
+
+// Record types
+const (
+	RecordTypeCmd   RecordType = "cmd"
+	RecordTypeState RecordType = "state"
+	RecordTypeVideo RecordType = "video"
+)
+
+// RecordType represents a record type
+type RecordType string
+
+// Record represents an item captured by a recorder
+// Records are serialized as JSON, one per line
+type Record struct {
+	Data    []byte        `json:"data"`
+	Elapsed time.Duration `json:"elapsed"` // Time elapsed since the recorder was created
+	Type    RecordType    `json:"type"`
+}
+
+// Recorder represents an object capturing cmds sent, and states and video packets received, which turns
+// real flights into fixtures that can be replayed. Provide it to the drone with the WithRecorder option.
+type Recorder struct {
+	e     *json.Encoder
+	err   error
+	m     *sync.Mutex // Locks e and err
+	start time.Time
+}
+
+// NewRecorder creates a new recorder writing records to w
+// Writes are not buffered: wrap w in a bufio.Writer if needed
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{
+		e:     json.NewEncoder(w),
+		m:     &sync.Mutex{},
+		start: time.Now(),
+	}
+}
+
+// Err returns the first error that occurred while writing records, after which records are not written
+// anymore
+func (r *Recorder) Err() error {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.err
+}
+
+func (r *Recorder) record(t RecordType, data []byte) {
+	// Lock
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	// A previous write failed
+	if r.err != nil {
+		return
+	}
+
+	// Write
+	if err := r.e.Encode(Record{
+		Data:    data,
+		Elapsed: time.Since(r.start),
+		Type:    t,
+	}); err != nil {
+		r.err = fmt.Errorf("astitello: writing record failed: %w", err)
+		return
+	}
+}
+
+// startOffline makes the drone able to dispatch states and video events while it is not connected, and returns
+// a function that must be called once done
+func (d *Drone) startOffline(ctx context.Context, action string) (stop func(), err error) {
+	// Check connection state
+	if d.Connected() {
+		err = fmt.Errorf("astitello: can't %s while connected", action)
+		return
+	}
+
+	// Create context
+	ctx, cancel := context.WithCancel(ctx)
+
+	// Start eventer
+	go d.e.Start(ctx)
+
+	// Create frame parser
+	d.vfp = nil
+	if d.o.videoFrames || d.o.videoDecoder != nil {
+		d.vfp = newVideoFrameParser()
+	}
+
+	// Events are dispatched directly since timing is honoured
+	d.vb = nil
+
+	// Start decoder
+	wg := &sync.WaitGroup{}
+	d.vd = nil
+	if d.o.videoDecoder != nil {
+		d.vd = newVideoDecoder(d.o.videoDecoder, videoDecoderSize)
+		d.vd.dropped = &d.vs.DecoderDropped
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.vd.start(ctx, func(i image.Image) { d.e.Dispatch(VideoImageEvent, i) }, d.l)
+		}()
+	}
+
+	// The decoder goroutine only stops once the context is done, therefore it must be cancelled before waiting
+	stop = func() {
+		cancel()
+		wg.Wait()
+		d.e.Stop()
+	}
+	return
+}
+
+func (d *Drone) record(t RecordType, data []byte) {
+	if d.o.recorder != nil {
+		d.o.recorder.record(t, data)
+	}
+}
+
+// Replay reads records written by a recorder and feeds states and video packets back through the event
+// system, with their original timing, until all records have been read or the context is done. Cmd records
+// are skipped. The drone must not be connected.
+func (d *Drone) Replay(ctx context.Context, r io.Reader) (err error) {
+	// Start offline
+	var stop func()
+	if stop, err = d.startOffline(ctx, "replay"); err != nil {
+		return
+	}
+	defer stop()
+
+	// Loop through records
+	dec := json.NewDecoder(r)
+	cd := newCrashDetector(d.o)
+	fd := newFlightDetector(d.o)
+	start := time.Now()
+	for {
+		// Decode
+		var rec Record
+		if err = dec.Decode(&rec); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+				break
+			}
+			err = fmt.Errorf("astitello: decoding record failed: %w", err)
+			return
+		}
+
+		// Wait
+		if delta := rec.Elapsed - time.Since(start); delta > 0 {
+			select {
+			case <-time.After(delta):
+			case <-ctx.Done():
+				err = ctx.Err()
+				return
+			}
+		} else if err = ctx.Err(); err != nil {
+			return
+		}
+
+		// Switch on type
+		switch rec.Type {
+		case RecordTypeState:
+			d.handleRawState(string(bytes.TrimSpace(rec.Data)), cd, fd)
+		case RecordTypeVideo:
+			d.dispatchVideoPacket(rec.Data)
+		}
+	}
+	return
+}
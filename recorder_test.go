@@ -0,0 +1,129 @@
+package astitello
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	// Set up and start
+	buf := &bytes.Buffer{}
+	r := NewRecorder(buf)
+	d, _, s, v, teardown := setupAndStart(t, WithRecorder(r))
+	defer teardown()
+
+	// Take off
+	if err := d.TakeOff(); err != nil {
+		t.Error(fmt.Errorf("test: taking off failed: %w", err))
+	}
+
+	// Write state and video packet
+	if _, err := s.conn.Write([]byte(strState)); err != nil {
+		t.Error(fmt.Errorf("test: writing state failed: %w", err))
+	}
+	time.Sleep(10 * time.Millisecond)
+	if _, err := v.conn.Write([]byte("packet")); err != nil {
+		t.Error(fmt.Errorf("test: writing video packet failed: %w", err))
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	// Close
+	d.Close()
+	if err := r.Err(); err != nil {
+		t.Error(fmt.Errorf("test: recording failed: %w", err))
+	}
+
+	// Check records
+	var rs []string
+	dec := json.NewDecoder(bytes.NewReader(buf.Bytes()))
+	for dec.More() {
+		var rec Record
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatal(fmt.Errorf("test: decoding record failed: %w", err))
+		}
+		rs = append(rs, fmt.Sprintf("%s %s", rec.Type, rec.Data))
+	}
+	if e := []string{"cmd command", "cmd sdk?", "cmd sn?", "cmd takeoff", "state " + strState, "video packet"}; !reflect.DeepEqual(e, rs) {
+		t.Errorf("expected %+v, got %+v", e, rs)
+	}
+
+	// Replay
+	d = New(nil)
+	m := &sync.Mutex{}
+	var ps []string
+	var ss []State
+	d.On(StateEvent, StateEventHandler(func(s State) {
+		m.Lock()
+		defer m.Unlock()
+		ss = append(ss, s)
+	}))
+	d.On(VideoPacketEvent, VideoPacketEventHandler(func(p []byte) {
+		m.Lock()
+		defer m.Unlock()
+		ps = append(ps, string(p))
+	}))
+	if err := d.Replay(context.Background(), bytes.NewReader(buf.Bytes())); err != nil {
+		t.Error(fmt.Errorf("test: replaying failed: %w", err))
+	}
+
+	// Check events
+	time.Sleep(10 * time.Millisecond)
+	m.Lock()
+	defer m.Unlock()
+	if e := []State{expectedState}; !reflect.DeepEqual(e, ss) {
+		t.Errorf("expected %+v, got %+v", e, ss)
+	}
+	if e := []string{"packet"}; !reflect.DeepEqual(e, ps) {
+		t.Errorf("expected %+v, got %+v", e, ps)
+	}
+	if e, g := expectedState, d.State(); e != g {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+}
+
+func TestReplayVideoDecoder(t *testing.T) {
+	// Create records
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	kf := bytes.Join([][]byte{nalUnit(nalUnitTypeSPS, 0x1), nalUnit(nalUnitTypePPS, 0x1), nalUnit(nalUnitTypeSliceIDR, 0x80)}, nil)
+	for _, rec := range []Record{
+		{Data: kf, Type: RecordTypeVideo},
+		{Data: nalUnit(nalUnitTypeSlice, 0x80), Elapsed: time.Millisecond, Type: RecordTypeVideo},
+		{Data: []byte(strState), Elapsed: 50 * time.Millisecond, Type: RecordTypeState},
+	} {
+		if err := enc.Encode(rec); err != nil {
+			t.Fatal(fmt.Errorf("test: encoding record failed: %w", err))
+		}
+	}
+
+	// Handle images
+	d := New(nil, WithVideoDecoder(mockedDecoder{}))
+	images := make(chan bool, 2)
+	d.On(VideoImageEvent, VideoImageEventHandler(func(image.Image) { images <- true }))
+
+	// Replay must return even though the decoder goroutine is running
+	done := make(chan error, 1)
+	go func() { done <- d.Replay(context.Background(), bytes.NewReader(buf.Bytes())) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(fmt.Errorf("test: replaying failed: %w", err))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected replay to return")
+	}
+
+	// Check images
+	select {
+	case <-images:
+	default:
+		t.Error("expected image")
+	}
+}
@@ -0,0 +1,163 @@
+package astitello
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Sequence represents a list of steps executed in order, which makes scripted flights less verbose
+// Build it with NewSequence and execute it with Run
+type Sequence struct {
+	abort func(err error)
+	c     Controller
+	steps []sequenceStep
+}
+
+type sequenceStep struct {
+	fn   func(ctx context.Context) error
+	name string
+}
+
+// NewSequence creates a new sequence executing cmds on the drone
+func (d *Drone) NewSequence() *Sequence {
+	return NewSequence(d)
+}
+
+// NewSequence creates a new sequence executing cmds on the controller, which can be a simulator
+func NewSequence(c Controller) *Sequence {
+	return &Sequence{c: c}
+}
+
+func (s *Sequence) add(name string, fn func() error) *Sequence {
+	s.steps = append(s.steps, sequenceStep{
+		fn:   func(context.Context) error { return fn() },
+		name: name,
+	})
+	return s
+}
+
+// OnAbort sets a hook executed when the sequence stops before its last step, either because a step failed
+// or because the context was cancelled
+func (s *Sequence) OnAbort(fn func(err error)) *Sequence {
+	s.abort = fn
+	return s
+}
+
+// LandOnAbort makes the drone land when the sequence stops before its last step
+func (s *Sequence) LandOnAbort() *Sequence {
+	return s.OnAbort(func(error) { s.c.Land() })
+}
+
+// TakeOff adds a take off step
+func (s *Sequence) TakeOff() *Sequence {
+	return s.add("takeoff", s.c.TakeOff)
+}
+
+// Land adds a land step
+func (s *Sequence) Land() *Sequence {
+	return s.add("land", s.c.Land)
+}
+
+// Up adds an up step
+func (s *Sequence) Up(x int) *Sequence {
+	return s.add(fmt.Sprintf("up %d", x), func() error { return s.c.Up(x) })
+}
+
+// Down adds a down step
+func (s *Sequence) Down(x int) *Sequence {
+	return s.add(fmt.Sprintf("down %d", x), func() error { return s.c.Down(x) })
+}
+
+// Left adds a left step
+func (s *Sequence) Left(x int) *Sequence {
+	return s.add(fmt.Sprintf("left %d", x), func() error { return s.c.Left(x) })
+}
+
+// Right adds a right step
+func (s *Sequence) Right(x int) *Sequence {
+	return s.add(fmt.Sprintf("right %d", x), func() error { return s.c.Right(x) })
+}
+
+// Forward adds a forward step
+func (s *Sequence) Forward(x int) *Sequence {
+	return s.add(fmt.Sprintf("forward %d", x), func() error { return s.c.Forward(x) })
+}
+
+// Back adds a back step
+func (s *Sequence) Back(x int) *Sequence {
+	return s.add(fmt.Sprintf("back %d", x), func() error { return s.c.Back(x) })
+}
+
+// RotateClockwise adds a clockwise rotation step
+func (s *Sequence) RotateClockwise(x int) *Sequence {
+	return s.add(fmt.Sprintf("cw %d", x), func() error { return s.c.RotateClockwise(x) })
+}
+
+// RotateCounterClockwise adds a counter clockwise rotation step
+func (s *Sequence) RotateCounterClockwise(x int) *Sequence {
+	return s.add(fmt.Sprintf("ccw %d", x), func() error { return s.c.RotateCounterClockwise(x) })
+}
+
+// Flip adds a flip step
+func (s *Sequence) Flip(x FlipDirection) *Sequence {
+	return s.add(fmt.Sprintf("flip %s", x), func() error { return s.c.Flip(x) })
+}
+
+// Go adds a go step
+func (s *Sequence) Go(x, y, z, speed int) *Sequence {
+	return s.add(fmt.Sprintf("go %d %d %d %d", x, y, z, speed), func() error { return s.c.Go(x, y, z, speed) })
+}
+
+// Curve adds a curve step
+func (s *Sequence) Curve(x1, y1, z1, x2, y2, z2, speed int) *Sequence {
+	return s.add(fmt.Sprintf("curve %d %d %d %d %d %d %d", x1, y1, z1, x2, y2, z2, speed), func() error { return s.c.Curve(x1, y1, z1, x2, y2, z2, speed) })
+}
+
+// SetSpeed adds a speed step
+func (s *Sequence) SetSpeed(x int) *Sequence {
+	return s.add(fmt.Sprintf("speed %d", x), func() error { return s.c.SetSpeed(x) })
+}
+
+// Wait adds a step waiting for the provided duration
+func (s *Sequence) Wait(d time.Duration) *Sequence {
+	s.steps = append(s.steps, sequenceStep{
+		fn: func(ctx context.Context) error {
+			t := time.NewTimer(d)
+			defer t.Stop()
+			select {
+			case <-t.C:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+		name: fmt.Sprintf("wait %s", d),
+	})
+	return s
+}
+
+// Run executes steps in order and stops at the first error
+// The context is checked between steps: cancelling it doesn't interrupt the cmd being executed
+func (s *Sequence) Run(ctx context.Context) (err error) {
+	// Loop through steps
+	for idx, step := range s.steps {
+		// Check context
+		if err = ctx.Err(); err != nil {
+			err = fmt.Errorf("astitello: context error before step %d (%s): %w", idx+1, step.name, err)
+			break
+		}
+
+		// Execute step
+		if err = step.fn(ctx); err != nil {
+			err = fmt.Errorf("astitello: step %d (%s) failed: %w", idx+1, step.name, err)
+			break
+		}
+	}
+
+	// Abort
+	if err != nil && s.abort != nil {
+		s.abort(err)
+	}
+	return
+}
@@ -0,0 +1,67 @@
+package astitello
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSequence(t *testing.T) {
+	// Set up and start
+	d, c, _, _, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Run
+	if err := d.NewSequence().TakeOff().Forward(1).RotateClockwise(1).Wait(time.Millisecond).Land().Run(context.Background()); err != nil {
+		t.Error(fmt.Errorf("test: running sequence failed: %w", err))
+	}
+	if e, g := []string{"command", "sdk?", "sn?", "takeoff", "forward 1", "cw 1", "land"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+
+	// Cancelled context should land
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if err := d.NewSequence().TakeOff().Wait(time.Minute).Forward(1).LandOnAbort().Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %s, got %s", context.Canceled, err)
+	}
+	if e, g := []string{"command", "sdk?", "sn?", "takeoff", "forward 1", "cw 1", "land", "takeoff", "land"}, c.received(); !reflect.DeepEqual(e, g) {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+
+	// Failing step
+	var aborted error
+	err := New(nil).NewSequence().TakeOff().Land().OnAbort(func(err error) { aborted = err }).Run(context.Background())
+	if !errors.Is(err, ErrNotConnected) {
+		t.Errorf("expected %s, got %s", ErrNotConnected, err)
+	}
+	if aborted != err {
+		t.Errorf("expected %s, got %s", err, aborted)
+	}
+}
+
+func TestSequenceWithController(t *testing.T) {
+	// Start simulator
+	s := NewSimulator()
+	s.Start()
+	defer s.Close()
+
+	// Run
+	if err := NewSequence(s).TakeOff().Up(50).Down(20).Run(context.Background()); err != nil {
+		t.Error(fmt.Errorf("test: running sequence failed: %w", err))
+	}
+	if e, g := simulatorTakeOffHeight+30, s.State().Height; e != g {
+		t.Errorf("expected %d, got %d", e, g)
+	}
+
+	// Failing step should land
+	if err := NewSequence(s).Up(10).LandOnAbort().Run(context.Background()); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected %s, got %s", ErrInvalidArgument, err)
+	}
+	if e, g := 0, s.State().Height; e != g {
+		t.Errorf("expected %d, got %d", e, g)
+	}
+}
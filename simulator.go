@@ -0,0 +1,335 @@
+package astitello
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/asticode/go-astikit"
+)
+
+// Simulator
+var (
+	simulatorBatteryDrain   = 10 * time.Second // Flight time after which the battery loses 1%
+	simulatorStateInterval  = 100 * time.Millisecond
+	simulatorTakeOffHeight  = 80
+	simulatorTemperatureMin = 60
+)
+
+// ErrNotFlying is the error thrown by the simulator when a cmd requires the drone to be flying
+var ErrNotFlying = errors.New("astitello: not flying")
+
+// Simulator represents a fake drone that accepts all cmds, updates a plausible state and dispatches it
+// through the State event at a fixed interval. It is meant for developing and teaching without hardware.
+type Simulator struct {
+	cancel context.CancelFunc
+	ctx    context.Context
+	e      *eventer
+	flown  time.Duration
+	flying bool
+	m      *sync.Mutex // Locks flown, flying, s and speed
+	o      *sync.Once  // Limits Close()
+	s      State
+	speed  int
+	wg     *sync.WaitGroup
+}
+
+// NewSimulator creates a new simulator with a full battery
+func NewSimulator() *Simulator {
+	return &Simulator{
+		e: newEventer(),
+		m: &sync.Mutex{},
+		o: &sync.Once{},
+		s: State{
+			Battery:            100,
+			HighestTemperature: simulatorTemperatureMin + 2,
+			LowestTemperature:  simulatorTemperatureMin,
+		},
+		speed: 100,
+		wg:    &sync.WaitGroup{},
+	}
+}
+
+// Start starts dispatching states until the simulator is closed
+func (s *Simulator) Start() error {
+	// Create context
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	// Start eventer
+	go s.e.Start(s.ctx)
+
+	// Tick
+	s.wg.Add(1)
+	go s.tick()
+	return nil
+}
+
+// Close stops the simulator
+func (s *Simulator) Close() {
+	s.o.Do(func() {
+		if s.cancel != nil {
+			s.cancel()
+		}
+		s.wg.Wait()
+		s.e.Stop()
+	})
+}
+
+func (s *Simulator) tick() {
+	// Make sure to signal the goroutine is done
+	defer s.wg.Done()
+
+	// Create ticker
+	t := time.NewTicker(simulatorStateInterval)
+	defer t.Stop()
+
+	// Loop
+	for {
+		select {
+		case <-t.C:
+		case <-s.ctx.Done():
+			return
+		}
+
+		// Update state
+		s.m.Lock()
+		if s.flying {
+			s.flown += simulatorStateInterval
+			s.s.FlightTime = int(s.flown / time.Second)
+			if s.s.Battery = 100 - int(s.flown/simulatorBatteryDrain); s.s.Battery < 0 {
+				s.s.Battery = 0
+			}
+		}
+		st := s.s
+		s.m.Unlock()
+
+		// Dispatch
+		s.e.Dispatch(StateEvent, st)
+	}
+}
+
+// On adds an event handler
+func (s *Simulator) On(name string, h astikit.EventerHandler) {
+	s.e.On(name, h)
+}
+
+// State returns the simulated state
+func (s *Simulator) State() State {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.s
+}
+
+// TakeOff simulates a take off
+func (s *Simulator) TakeOff() error {
+	// Update state
+	s.m.Lock()
+	s.flying = true
+	s.s.Height = simulatorTakeOffHeight
+	s.s.FlightDistance = simulatorTakeOffHeight
+	s.m.Unlock()
+
+	// Dispatch
+	s.e.Dispatch(TakeOffEvent, nil)
+	return nil
+}
+
+// Land simulates a landing
+func (s *Simulator) Land() error {
+	// Update state
+	s.m.Lock()
+	s.land()
+	s.m.Unlock()
+
+	// Dispatch
+	s.e.Dispatch(LandEvent, nil)
+	return nil
+}
+
+// Emergency simulates motors being stopped
+func (s *Simulator) Emergency() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.land()
+	return nil
+}
+
+func (s *Simulator) land() {
+	s.flying = false
+	s.s.Height = 0
+	s.s.FlightDistance = 0
+	s.s.Speed = Speed{}
+}
+
+// move updates the height by dz if the drone is flying
+// Assumes the mutex is locked
+func (s *Simulator) move(name string, dz int) (err error) {
+	// Not flying
+	if !s.flying {
+		err = fmt.Errorf("astitello: %s failed: %w", name, ErrNotFlying)
+		return
+	}
+
+	// Update height
+	if s.s.Height += dz; s.s.Height < 0 {
+		s.s.Height = 0
+	}
+	s.s.FlightDistance = s.s.Height
+	return
+}
+
+func (s *Simulator) translate(name string, x, dz int) (err error) {
+	// Validate
+	if x < 20 || x > 500 {
+		err = fmt.Errorf("astitello: %s %d is not between 20 and 500: %w", name, x, ErrInvalidArgument)
+		return
+	}
+
+	// Move
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.move(name, dz)
+}
+
+// Up simulates flying up with distance x cm
+func (s *Simulator) Up(x int) error {
+	return s.translate("up", x, x)
+}
+
+// Down simulates flying down with distance x cm
+func (s *Simulator) Down(x int) error {
+	return s.translate("down", x, -x)
+}
+
+// Left simulates flying left with distance x cm
+func (s *Simulator) Left(x int) error {
+	return s.translate("left", x, 0)
+}
+
+// Right simulates flying right with distance x cm
+func (s *Simulator) Right(x int) error {
+	return s.translate("right", x, 0)
+}
+
+// Forward simulates flying forward with distance x cm
+func (s *Simulator) Forward(x int) error {
+	return s.translate("forward", x, 0)
+}
+
+// Back simulates flying backward with distance x cm
+func (s *Simulator) Back(x int) error {
+	return s.translate("back", x, 0)
+}
+
+// RotateClockwise simulates rotating x degree clockwise
+func (s *Simulator) RotateClockwise(x int) error {
+	return s.rotate("cw", x)
+}
+
+// RotateCounterClockwise simulates rotating x degree counter-clockwise
+func (s *Simulator) RotateCounterClockwise(x int) error {
+	return s.rotate("ccw", -x)
+}
+
+func (s *Simulator) rotate(name string, x int) (err error) {
+	// Validate
+	if abs(x) < 1 || abs(x) > 3600 {
+		err = fmt.Errorf("astitello: %s %d is not between 1 and 3600: %w", name, abs(x), ErrInvalidArgument)
+		return
+	}
+
+	// Lock
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	// Move
+	if err = s.move(name, 0); err != nil {
+		return
+	}
+
+	// Update yaw so that it stays between -180 and 180
+	s.s.Attitude.Yaw = ((s.s.Attitude.Yaw+x+180)%360+360)%360 - 180
+	return
+}
+
+// Flip simulates a flip
+func (s *Simulator) Flip(x FlipDirection) (err error) {
+	// Validate
+	if err = validateFlipDirection(x); err != nil {
+		return
+	}
+
+	// Move
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.move("flip", 0)
+}
+
+// Go simulates flying to x y z at speed (cm/s)
+func (s *Simulator) Go(x, y, z, speed int) (err error) {
+	// Validate
+	if err = validateGo(x, y, z, speed); err != nil {
+		return
+	}
+
+	// Move
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.move("go", z)
+}
+
+// Curve simulates flying a curve ending at x2 y2 z2 at speed (cm/s)
+func (s *Simulator) Curve(x1, y1, z1, x2, y2, z2, speed int) (err error) {
+	// Validate
+	if err = validateCurve(x1, y1, z1, x2, y2, z2, speed); err != nil {
+		return
+	}
+
+	// Move
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.move("curve", z2)
+}
+
+// SetSpeed simulates setting speed to x cm/s
+func (s *Simulator) SetSpeed(x int) (err error) {
+	// Validate
+	if x < 10 || x > 100 {
+		err = fmt.Errorf("astitello: speed %d is not between 10 and 100: %w", x, ErrInvalidArgument)
+		return
+	}
+
+	// Update
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.speed = x
+	return
+}
+
+// SetSticks simulates RC control. Sticks positions are reflected in the state's speed.
+func (s *Simulator) SetSticks(lr, fb, ud, y int) (err error) {
+	// Validate
+	if err = validateSticks(lr, fb, ud, y); err != nil {
+		return
+	}
+
+	// Lock
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	// Not flying
+	if !s.flying {
+		return
+	}
+
+	// Update speed
+	s.s.Speed = Speed{X: fb * s.speed / 100, Y: lr * s.speed / 100, Z: ud * s.speed / 100}
+	return
+}
+
+// Hover simulates all sticks being set to their neutral position
+func (s *Simulator) Hover() error {
+	return s.SetSticks(0, 0, 0, 0)
+}
@@ -0,0 +1,115 @@
+package astitello
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSimulator(t *testing.T) {
+	// Update defaults
+	i := simulatorStateInterval
+	simulatorStateInterval = 5 * time.Millisecond
+	defer func() { simulatorStateInterval = i }()
+
+	// Start
+	var c Controller = NewSimulator()
+	s := c.(*Simulator)
+	if err := s.Start(); err != nil {
+		t.Fatal(fmt.Errorf("test: starting simulator failed: %w", err))
+	}
+	defer s.Close()
+
+	// Handle events
+	states := make(chan State, 100)
+	c.On(StateEvent, StateEventHandler(func(s State) {
+		select {
+		case states <- s:
+		default:
+		}
+	}))
+	tookOff := make(chan bool, 1)
+	c.On(TakeOffEvent, func(interface{}) { tookOff <- true })
+
+	// Not flying
+	if err := c.Up(50); !errors.Is(err, ErrNotFlying) {
+		t.Errorf("expected %s, got %s", ErrNotFlying, err)
+	}
+
+	// Fly
+	if err := c.TakeOff(); err != nil {
+		t.Error(fmt.Errorf("test: taking off failed: %w", err))
+	}
+	select {
+	case <-tookOff:
+	case <-time.After(time.Second):
+		t.Error("expected take off event")
+	}
+	for _, f := range []func() error{
+		func() error { return c.Up(50) },
+		func() error { return c.Down(30) },
+		func() error { return c.Forward(100) },
+		func() error { return c.RotateClockwise(270) },
+		func() error { return c.Flip(FlipLeft) },
+	} {
+		if err := f(); err != nil {
+			t.Error(fmt.Errorf("test: cmd failed: %w", err))
+		}
+	}
+	if e, g := simulatorTakeOffHeight+20, c.State().Height; e != g {
+		t.Errorf("expected height %d, got %d", e, g)
+	}
+	if e, g := -90, c.State().Attitude.Yaw; e != g {
+		t.Errorf("expected yaw %d, got %d", e, g)
+	}
+
+	// Invalid arguments
+	if err := c.Up(10); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected %s, got %s", ErrInvalidArgument, err)
+	}
+	if err := c.SetSticks(0, 101, 0, 0); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected %s, got %s", ErrInvalidArgument, err)
+	}
+
+	// State should be dispatched
+	select {
+	case s := <-states:
+		if s.Battery <= 0 {
+			t.Errorf("expected battery > 0, got %d", s.Battery)
+		}
+	case <-time.After(time.Second):
+		t.Error("expected state event")
+	}
+
+	// Land
+	if err := c.Land(); err != nil {
+		t.Error(fmt.Errorf("test: landing failed: %w", err))
+	}
+	if e, g := 0, c.State().Height; e != g {
+		t.Errorf("expected height %d, got %d", e, g)
+	}
+}
+
+func TestSimulatorBattery(t *testing.T) {
+	// Update defaults
+	i, d := simulatorStateInterval, simulatorBatteryDrain
+	simulatorStateInterval, simulatorBatteryDrain = time.Millisecond, time.Millisecond
+	defer func() { simulatorStateInterval, simulatorBatteryDrain = i, d }()
+
+	// Start
+	s := NewSimulator()
+	s.Start()
+	defer s.Close()
+
+	// Battery should drain only while flying
+	time.Sleep(10 * time.Millisecond)
+	if e, g := 100, s.State().Battery; e != g {
+		t.Errorf("expected %d, got %d", e, g)
+	}
+	s.TakeOff()
+	time.Sleep(10 * time.Millisecond)
+	if g := s.State().Battery; g >= 100 {
+		t.Errorf("expected battery < 100, got %d", g)
+	}
+}
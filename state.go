@@ -2,51 +2,224 @@ package astitello
 
 import (
 	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
 )
 
 // State represents the drone's state
+// Its JSON representation is stable and its keys are suffixed with their unit when relevant
 type State struct {
-	Acceleration       Acceleration // The acceleration
-	Attitude           Attitude     // The attitude
-	Barometer          float64      // The barometer measurement in cm
-	Battery            int          // The percentage of the current battery level
-	FlightDistance     int          // The time of flight distance in cm
-	FlightTime         int          // The amount of time the motor has been used
-	Height             int          // The height in cm
-	HighestTemperature int          // The highest temperature in degree Celsius
-	LowestTemperature  int          // The lowest temperature in degree Celsius
-	Speed              Speed        // The speed
+	Acceleration       Acceleration `json:"acceleration"`   // The acceleration
+	Attitude           Attitude     `json:"attitude"`       // The attitude
+	Barometer          float64      `json:"baro_cm"`        // The barometer measurement in cm
+	Battery            int          `json:"battery"`        // The percentage of the current battery level
+	FlightDistance     int          `json:"tof_cm"`         // The time of flight distance in cm
+	FlightTime         int          `json:"flight_time_s"`  // The amount of time the motor has been used in s
+	Height             int          `json:"height_cm"`      // The height in cm
+	HighestTemperature int          `json:"temp_highest_c"` // The highest temperature in degree Celsius
+	LowestTemperature  int          `json:"temp_lowest_c"`  // The lowest temperature in degree Celsius
+	Speed              Speed        `json:"speed"`          // The speed
 }
 
 // Acceleration represents the drone's acceleration
 type Acceleration struct {
-	X float64
-	Y float64
-	Z float64
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
 }
 
 // Attitude represents the drone's attitude
 type Attitude struct {
-	Pitch int // The degree of the attitude pitch
-	Roll  int // The degree of the attitude roll
-	Yaw   int // The degree of the attitude yaw
+	Pitch int `json:"pitch_deg"` // The degree of the attitude pitch
+	Roll  int `json:"roll_deg"`  // The degree of the attitude roll
+	Yaw   int `json:"yaw_deg"`   // The degree of the attitude yaw
 }
 
 // Speed represents the drone's speed
 type Speed struct {
-	X int
-	Y int
-	Z int
+	X int `json:"x"`
+	Y int `json:"y"`
+	Z int `json:"z"`
 }
 
+// stateFieldsCount is the number of fields sent by the drone that newState parses
+const stateFieldsCount = 16
+
+// stateFieldsMask has one bit set per field newState parses
+const stateFieldsMask uint16 = 1<<stateFieldsCount - 1
+
 func newState(i string) (s State, err error) {
-	var n int
-	if n, err = fmt.Sscanf(i, "pitch:%d;roll:%d;yaw:%d;vgx:%d;vgy:%d;vgz:%d;templ:%d;temph:%d;tof:%d;h:%d;bat:%d;baro:%f;time:%d;agx:%f;agy:%f;agz:%f;", &s.Attitude.Pitch, &s.Attitude.Roll, &s.Attitude.Yaw, &s.Speed.X, &s.Speed.Y, &s.Speed.Z, &s.LowestTemperature, &s.HighestTemperature, &s.FlightDistance, &s.Height, &s.Battery, &s.Barometer, &s.FlightTime, &s.Acceleration.X, &s.Acceleration.Y, &s.Acceleration.Z); err != nil {
-		err = fmt.Errorf("astitello: scanf failed: %w", err)
-		return
-	} else if n != 16 {
-		err = fmt.Errorf("astitello: scanf only parsed %d items, expected 10", n)
+	// Loop through fields
+	var seen uint16
+	for len(i) > 0 {
+		// Get field
+		var f string
+		if idx := strings.IndexByte(i, ';'); idx >= 0 {
+			f, i = i[:idx], i[idx+1:]
+		} else {
+			f, i = i, ""
+		}
+
+		// Trailing characters such as "\r\n" are not a field
+		if f = strings.TrimSpace(f); f == "" {
+			continue
+		}
+
+		// Split key and value
+		idx := strings.IndexByte(f, ':')
+		if idx < 0 {
+			err = fmt.Errorf("astitello: invalid state field %q", f)
+			return
+		}
+		k, v := f[:idx], f[idx+1:]
+
+		// Get destination
+		var ip *int
+		var fp *float64
+		var bit uint
+		switch k {
+		case "agx":
+			fp, bit = &s.Acceleration.X, 0
+		case "agy":
+			fp, bit = &s.Acceleration.Y, 1
+		case "agz":
+			fp, bit = &s.Acceleration.Z, 2
+		case "bat":
+			ip, bit = &s.Battery, 3
+		case "baro":
+			fp, bit = &s.Barometer, 4
+		case "h":
+			ip, bit = &s.Height, 5
+		case "pitch":
+			ip, bit = &s.Attitude.Pitch, 6
+		case "roll":
+			ip, bit = &s.Attitude.Roll, 7
+		case "temph":
+			ip, bit = &s.HighestTemperature, 8
+		case "templ":
+			ip, bit = &s.LowestTemperature, 9
+		case "time":
+			ip, bit = &s.FlightTime, 10
+		case "tof":
+			ip, bit = &s.FlightDistance, 11
+		case "vgx":
+			ip, bit = &s.Speed.X, 12
+		case "vgy":
+			ip, bit = &s.Speed.Y, 13
+		case "vgz":
+			ip, bit = &s.Speed.Z, 14
+		case "yaw":
+			ip, bit = &s.Attitude.Yaw, 15
+		default:
+			// Fields sent by other firmwares, such as mission pad ones, are ignored
+			continue
+		}
+
+		// Parse value
+		if ip != nil {
+			if *ip, err = strconv.Atoi(v); err != nil {
+				err = fmt.Errorf("astitello: parsing state field %s failed: %w", k, err)
+				return
+			}
+		} else if *fp, err = strconv.ParseFloat(v, 64); err != nil {
+			err = fmt.Errorf("astitello: parsing state field %s failed: %w", k, err)
+			return
+		}
+		seen |= 1 << bit
+	}
+
+	// Missing fields
+	// A duplicated field doesn't make up for a missing one
+	if seen != stateFieldsMask {
+		err = fmt.Errorf("astitello: only parsed %d state fields, expected %d", bits.OnesCount16(seen), stateFieldsCount)
 		return
 	}
 	return
 }
+
+// String implements the fmt.Stringer interface
+func (s State) String() string {
+	b := make([]byte, 0, 96)
+	b = append(b, "bat="...)
+	b = strconv.AppendInt(b, int64(s.Battery), 10)
+	b = append(b, "% h="...)
+	b = strconv.AppendInt(b, int64(s.Height), 10)
+	b = append(b, "cm tof="...)
+	b = strconv.AppendInt(b, int64(s.FlightDistance), 10)
+	b = append(b, "cm temp="...)
+	b = strconv.AppendInt(b, int64(s.LowestTemperature), 10)
+	b = append(b, '-')
+	b = strconv.AppendInt(b, int64(s.HighestTemperature), 10)
+	b = append(b, "C spd="...)
+	b = s.Speed.append(b)
+	b = append(b, " att="...)
+	b = s.Attitude.append(b)
+	return string(b)
+}
+
+// String implements the fmt.Stringer interface
+func (a Acceleration) String() string {
+	return string(a.append(make([]byte, 0, 32)))
+}
+
+// Magnitude returns the acceleration's euclidean norm
+func (a Acceleration) Magnitude() float64 {
+	return math.Sqrt(a.X*a.X + a.Y*a.Y + a.Z*a.Z)
+}
+
+func (a Acceleration) append(b []byte) []byte {
+	b = append(b, '(')
+	b = strconv.AppendFloat(b, a.X, 'f', -1, 64)
+	b = append(b, ',')
+	b = strconv.AppendFloat(b, a.Y, 'f', -1, 64)
+	b = append(b, ',')
+	b = strconv.AppendFloat(b, a.Z, 'f', -1, 64)
+	return append(b, ')')
+}
+
+// String implements the fmt.Stringer interface
+func (a Attitude) String() string {
+	return string(a.append(make([]byte, 0, 16)))
+}
+
+// IsLevel returns whether both pitch and roll are within tolerance degrees of 0
+func (a Attitude) IsLevel(tolerance int) bool {
+	return abs(a.Pitch) <= tolerance && abs(a.Roll) <= tolerance
+}
+
+func (a Attitude) append(b []byte) []byte {
+	return appendInts(b, a.Pitch, a.Roll, a.Yaw)
+}
+
+// String implements the fmt.Stringer interface
+func (s Speed) String() string {
+	return string(s.append(make([]byte, 0, 16)))
+}
+
+// Magnitude returns the speed's euclidean norm
+func (s Speed) Magnitude() float64 {
+	return math.Sqrt(float64(s.X*s.X + s.Y*s.Y + s.Z*s.Z))
+}
+
+func (s Speed) append(b []byte) []byte {
+	return appendInts(b, s.X, s.Y, s.Z)
+}
+
+func appendInts(b []byte, x, y, z int) []byte {
+	b = append(b, '(')
+	b = strconv.AppendInt(b, int64(x), 10)
+	b = append(b, ',')
+	b = strconv.AppendInt(b, int64(y), 10)
+	b = append(b, ',')
+	b = strconv.AppendInt(b, int64(z), 10)
+	return append(b, ')')
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,81 @@
+package astitello
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+var telemetryCSVHeader = []string{
+	"elapsed_ms",
+	"pitch_deg",
+	"roll_deg",
+	"yaw_deg",
+	"speed_x",
+	"speed_y",
+	"speed_z",
+	"temp_lowest_c",
+	"temp_highest_c",
+	"tof_cm",
+	"height_cm",
+	"battery",
+	"baro_cm",
+	"flight_time_s",
+	"acceleration_x",
+	"acceleration_y",
+	"acceleration_z",
+}
+
+// LogTelemetryCSV writes a header row and then one CSV row per state update to the writer until the
+// context is cancelled
+// The first column is the number of milliseconds elapsed since the method was called
+func (d *Drone) LogTelemetryCSV(ctx context.Context, w io.Writer) (err error) {
+	// Create csv writer, which is buffered
+	cw := csv.NewWriter(w)
+	start := time.Now()
+
+	// Write header
+	if err = cw.Write(telemetryCSVHeader); err != nil {
+		err = fmt.Errorf("astitello: writing header failed: %w", err)
+		return
+	}
+
+	// Write rows
+	return d.writeUntil(ctx, StateEvent, func(payload interface{}) (err error) {
+		if err = cw.Write(telemetryCSVRow(time.Since(start), payload.(State))); err != nil {
+			err = fmt.Errorf("astitello: writing row failed: %w", err)
+		}
+		return
+	}, func() (err error) {
+		cw.Flush()
+		if err = cw.Error(); err != nil {
+			err = fmt.Errorf("astitello: flushing failed: %w", err)
+		}
+		return
+	})
+}
+
+func telemetryCSVRow(elapsed time.Duration, s State) []string {
+	return []string{
+		strconv.FormatInt(int64(elapsed/time.Millisecond), 10),
+		strconv.Itoa(s.Attitude.Pitch),
+		strconv.Itoa(s.Attitude.Roll),
+		strconv.Itoa(s.Attitude.Yaw),
+		strconv.Itoa(s.Speed.X),
+		strconv.Itoa(s.Speed.Y),
+		strconv.Itoa(s.Speed.Z),
+		strconv.Itoa(s.LowestTemperature),
+		strconv.Itoa(s.HighestTemperature),
+		strconv.Itoa(s.FlightDistance),
+		strconv.Itoa(s.Height),
+		strconv.Itoa(s.Battery),
+		strconv.FormatFloat(s.Barometer, 'f', -1, 64),
+		strconv.Itoa(s.FlightTime),
+		strconv.FormatFloat(s.Acceleration.X, 'f', -1, 64),
+		strconv.FormatFloat(s.Acceleration.Y, 'f', -1, 64),
+		strconv.FormatFloat(s.Acceleration.Z, 'f', -1, 64),
+	}
+}
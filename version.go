@@ -0,0 +1,31 @@
+package astitello
+
+// LibraryVersion is the version of this library, which is the only place it is defined
+const LibraryVersion = "0.1.0"
+
+// VersionInfo represents the versions of the library and of the connected drone, which is useful in bug
+// reports and telemetry headers
+type VersionInfo struct {
+	Library      string `json:"library"`       // The library version, always set
+	SDK          string `json:"sdk"`           // The SDK version, empty when not connected or for SDK 1.3
+	SerialNumber string `json:"serial_number"` // The serial number, empty when not connected
+}
+
+// Version returns the library version and, if connected, the drone's SDK version and serial number
+// The drone's versions come from Capabilities(), which means they are probed the first time and cached until
+// the drone is closed.
+func (d *Drone) Version() (v VersionInfo) {
+	// Library
+	v.Library = LibraryVersion
+
+	// Not connected
+	if !d.Connected() {
+		return
+	}
+
+	// Drone
+	c := d.Capabilities()
+	v.SDK = c.SDKVersion
+	v.SerialNumber = c.SerialNumber
+	return
+}
@@ -0,0 +1,38 @@
+package astitello
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	// Set up
+	d, c, s, v, err := setup(t)
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: setting up failed: %w", err))
+	}
+	defer func() {
+		d.Close()
+		c.close()
+		s.close()
+		v.close()
+	}()
+
+	// Library version should be populated when disconnected
+	if e, g := (VersionInfo{Library: LibraryVersion}), d.Version(); e != g {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+	if LibraryVersion == "" {
+		t.Error("expected library version, got empty")
+	}
+
+	// Start
+	if err = d.Start(); err != nil {
+		t.Fatal(fmt.Errorf("test: starting the drone failed: %w", err))
+	}
+
+	// Drone versions should be populated when connected
+	if e, g := (VersionInfo{Library: LibraryVersion, SDK: "20", SerialNumber: "0TQDG2KEDB4F7X"}), d.Version(); e != g {
+		t.Errorf("expected %+v, got %+v", e, g)
+	}
+}
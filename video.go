@@ -0,0 +1,267 @@
+package astitello
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/asticode/go-astikit"
+)
+
+// H264 NAL unit types
+const (
+	nalUnitTypeSlice    = 1
+	nalUnitTypeSliceIDR = 5
+	nalUnitTypeSEI      = 6
+	nalUnitTypeSPS      = 7
+	nalUnitTypePPS      = 8
+	nalUnitTypeAUD      = 9
+)
+
+// VideoFrame represents a complete H264 access unit
+type VideoFrame struct {
+	Data     []byte
+	Keyframe bool // Whether the access unit contains an IDR slice or an SPS
+}
+
+// VideoFrameEventHandler returns the proper EventHandler for the VideoFrame event
+func VideoFrameEventHandler(f func(f VideoFrame)) astikit.EventerHandler {
+	return func(payload interface{}) {
+		f(payload.(VideoFrame))
+	}
+}
+
+// videoFrameParser splits an H264 stream into access units.
+// Since an access unit is only known to be over once the next one starts, there's always one access unit
+// pending in the parser.
+type videoFrameParser struct {
+	buf      []byte
+	keyframe bool // Whether the pending access unit contains a keyframe NAL unit
+	nals     int  // Number of NAL units in the pending access unit
+	offset   int  // Position in buf from which start codes have not been looked for yet
+	vcl      bool // Whether the pending access unit contains a slice
+}
+
+func newVideoFrameParser() *videoFrameParser {
+	return &videoFrameParser{}
+}
+
+func (p *videoFrameParser) parse(b []byte) (fs []VideoFrame) {
+	// Append to buffer
+	p.buf = append(p.buf, b...)
+
+	// Loop through start codes
+	for {
+		// Look for next start code
+		i := bytes.Index(p.buf[p.offset:], videoStartCode)
+		if i < 0 {
+			// Start code may be split between two calls
+			if o := len(p.buf) - len(videoStartCode) + 1; o > p.offset {
+				p.offset = o
+			}
+			return
+		}
+		pos := p.offset + i
+
+		// We need both the NAL header and the first byte of the slice header
+		if pos+len(videoStartCode)+1 >= len(p.buf) {
+			p.offset = pos
+			return
+		}
+
+		// Data located before the first start code can't be used
+		if p.nals == 0 && pos > 0 {
+			p.buf = append(p.buf[:0], p.buf[pos:]...)
+			pos = 0
+		}
+
+		// Get NAL unit type
+		t := p.buf[pos+len(videoStartCode)] & 0x1f
+
+		// A new access unit starts when the pending one contains a slice and either the NAL unit is not a slice,
+		// or it's the first slice of a new picture (first_mb_in_slice is 0, which is coded as bit 1)
+		isSlice := t == nalUnitTypeSlice || t == nalUnitTypeSliceIDR
+		if p.vcl && ((isSlice && p.buf[pos+len(videoStartCode)+1]&0x80 > 0) ||
+			t == nalUnitTypeSEI || t == nalUnitTypeSPS || t == nalUnitTypePPS || t == nalUnitTypeAUD) {
+			// Append frame
+			f := VideoFrame{
+				Data:     make([]byte, pos),
+				Keyframe: p.keyframe,
+			}
+			copy(f.Data, p.buf[:pos])
+			fs = append(fs, f)
+
+			// Reset
+			p.buf = append(p.buf[:0], p.buf[pos:]...)
+			p.keyframe = false
+			p.nals = 0
+			p.vcl = false
+			pos = 0
+		}
+
+		// Update pending access unit
+		p.nals++
+		if isSlice {
+			p.vcl = true
+		}
+		if t == nalUnitTypeSliceIDR || t == nalUnitTypeSPS {
+			p.keyframe = true
+		}
+		p.offset = pos + len(videoStartCode)
+	}
+}
+
+// RecordVideo writes the raw H264 video stream to the file located at path until the context is cancelled
+// Video has to be started separately and the VideoPacket event must not be disabled
+func (d *Drone) RecordVideo(ctx context.Context, path string) (err error) {
+	// Create file
+	var f *os.File
+	if f, err = os.Create(path); err != nil {
+		err = fmt.Errorf("astitello: creating %s failed: %w", path, err)
+		return
+	}
+	defer f.Close()
+
+	// Record
+	if err = d.RecordVideoTo(ctx, f); err != nil {
+		err = fmt.Errorf("astitello: recording video to %s failed: %w", path, err)
+		return
+	}
+	return
+}
+
+// RecordVideoTo writes the raw H264 video stream to the writer until the context is cancelled
+// Video has to be started separately and the VideoPacket event must not be disabled
+func (d *Drone) RecordVideoTo(ctx context.Context, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	return d.writeUntil(ctx, VideoPacketEvent, func(payload interface{}) (err error) {
+		if _, err = bw.Write(payload.([]byte)); err != nil {
+			err = fmt.Errorf("astitello: writing video packet failed: %w", err)
+		}
+		return
+	}, func() (err error) {
+		if err = bw.Flush(); err != nil {
+			err = fmt.Errorf("astitello: flushing failed: %w", err)
+		}
+		return
+	})
+}
+
+// FeedVideoFromFile reads the raw H264 stream located at path, such as one written by RecordVideo, and feeds
+// it through the event system as if it was received from the drone, until the whole file has been fed or the
+// context is done. It is meant to develop video pipelines without a drone.
+// Each NAL unit is split in datagrams of the size set with WithVideoMTU so that packets are reassembled the same
+// way, and pictures are paced at 30 per second. The drone must not be connected.
+func (d *Drone) FeedVideoFromFile(ctx context.Context, path string) (err error) {
+	// Read file
+	var b []byte
+	if b, err = ioutil.ReadFile(path); err != nil {
+		err = fmt.Errorf("astitello: reading %s failed: %w", path, err)
+		return
+	}
+
+	// Start offline
+	var stop func()
+	if stop, err = d.startOffline(ctx, "feed video"); err != nil {
+		return
+	}
+	defer stop()
+
+	// Loop through NAL units
+	var buf []byte
+	var pictures int
+	for _, n := range splitNALUnits(b) {
+		// Wait before each new picture
+		if len(n) > len(videoStartCode)+1 && isFirstSlice(n[len(videoStartCode)], n[len(videoStartCode)+1]) {
+			if pictures++; pictures > 1 {
+				select {
+				case <-time.After(videoFeedInterval):
+				case <-ctx.Done():
+					err = ctx.Err()
+					return
+				}
+			}
+		} else if err = ctx.Err(); err != nil {
+			return
+		}
+
+		// Loop through datagrams
+		for i := 0; i < len(n); i += d.o.videoMTU {
+			// Get datagram
+			dg := n[i:]
+			if len(dg) > d.o.videoMTU {
+				dg = dg[:d.o.videoMTU]
+			}
+
+			// Update stats
+			atomic.AddUint64(&d.vs.Bytes, uint64(len(dg)))
+			atomic.AddUint64(&d.vs.Datagrams, 1)
+
+			// Handle datagram
+			buf = d.handleVideoDatagram(buf, dg)
+		}
+	}
+
+	// Flush
+	d.dispatchVideoPacket(buf)
+	return
+}
+
+// splitNALUnits splits a raw H264 stream before each start code. Data located before the first start code is
+// returned as is.
+func splitNALUnits(b []byte) (ns [][]byte) {
+	for len(b) > 0 {
+		// Look for the next start code, skipping the one the unit starts with
+		i := bytes.Index(b[1:], videoStartCode)
+		if i < 0 {
+			ns = append(ns, b)
+			return
+		}
+		ns = append(ns, b[:i+1])
+		b = b[i+1:]
+	}
+	return
+}
+
+// isFirstSlice returns whether a NAL unit is the first slice of a picture based on its header and the first
+// byte of its slice header (first_mb_in_slice is 0, which is coded as bit 1)
+func isFirstSlice(header, next byte) bool {
+	t := header & 0x1f
+	return (t == nalUnitTypeSlice || t == nalUnitTypeSliceIDR) && next&0x80 > 0
+}
+
+// VideoChan returns a channel emitting raw H264 video packets until the context is done, after which it is
+// closed. It buffers up to 30 packets and, when the consumer is too slow, drops the oldest ones so that the
+// video goroutine is never blocked.
+// Video has to be started separately and the VideoPacket event must not be disabled
+func (d *Drone) VideoChan(ctx context.Context) <-chan []byte {
+	// Create channel
+	c := make(chan []byte, videoChanSize)
+	send := sendUntil(ctx, func() { close(c) })
+
+	// Handle video packets
+	d.onUntil(ctx, VideoPacketEvent, VideoPacketEventHandler(func(p []byte) {
+		send(func() {
+			// Loop until the packet has been sent
+			for {
+				select {
+				case c <- p:
+					return
+				default:
+					// Drop the oldest packet
+					select {
+					case <-c:
+					default:
+					}
+				}
+			}
+		})
+	}))
+	return c
+}
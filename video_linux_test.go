@@ -0,0 +1,38 @@
+package astitello
+
+import (
+	"fmt"
+	"syscall"
+	"testing"
+)
+
+func TestVideoReadBufferSize(t *testing.T) {
+	// Set up and start
+	n := 65536
+	d, _, _, _, teardown := setupAndStart(t, WithVideoReadBufferSize(n))
+	defer teardown()
+
+	// Get raw conn
+	d.mco.Lock()
+	rc, err := d.videoConn.SyscallConn()
+	d.mco.Unlock()
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: getting raw conn failed: %w", err))
+	}
+
+	// Get socket option
+	var g int
+	var gerr error
+	if err = rc.Control(func(fd uintptr) {
+		g, gerr = syscall.GetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_RCVBUF)
+	}); err != nil {
+		t.Fatal(fmt.Errorf("test: controlling raw conn failed: %w", err))
+	} else if gerr != nil {
+		t.Fatal(fmt.Errorf("test: getting socket option failed: %w", gerr))
+	}
+
+	// Linux doubles the value to allow space for bookkeeping overhead
+	if g < n {
+		t.Errorf("expected at least %d, got %d", n, g)
+	}
+}
@@ -0,0 +1,293 @@
+package astitello
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func nalUnit(t byte, b ...byte) []byte {
+	return append(append([]byte{0x0, 0x0, 0x0, 0x1, t}, b...), 0x2, 0x3)
+}
+
+func TestVideoFrameParser(t *testing.T) {
+	// Create stream
+	f1 := bytes.Join([][]byte{nalUnit(nalUnitTypeSPS, 0x1), nalUnit(nalUnitTypePPS, 0x1), nalUnit(nalUnitTypeSliceIDR, 0x80), nalUnit(nalUnitTypeSliceIDR, 0x40)}, nil)
+	f2 := nalUnit(nalUnitTypeSlice, 0x80)
+	f3 := bytes.Join([][]byte{nalUnit(nalUnitTypeAUD, 0x1), nalUnit(nalUnitTypeSlice, 0x80)}, nil)
+	f4 := nalUnit(nalUnitTypeSlice, 0x80)
+	stream := append([]byte{0x5, 0x6}, bytes.Join([][]byte{f1, f2, f3, f4}, nil)...)
+
+	// Parse in small chunks so that start codes get split
+	p := newVideoFrameParser()
+	var fs []VideoFrame
+	for i := 0; i < len(stream); i += 3 {
+		j := i + 3
+		if j > len(stream) {
+			j = len(stream)
+		}
+		fs = append(fs, p.parse(stream[i:j])...)
+	}
+
+	// Last access unit is still pending
+	e := []VideoFrame{
+		{Data: f1, Keyframe: true},
+		{Data: f2},
+		{Data: f3},
+	}
+	if !reflect.DeepEqual(fs, e) {
+		t.Errorf("expected %+v, got %+v", e, fs)
+	}
+}
+
+func TestRecordVideo(t *testing.T) {
+	// Set up and start
+	d, _, _, v, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Record
+	ctx, cancel := context.WithCancel(context.Background())
+	buf := &bytes.Buffer{}
+	errs := make(chan error)
+	go func() { errs <- d.RecordVideoTo(ctx, buf) }()
+
+	// Make sure the handler is registered before writing packets
+	time.Sleep(10 * time.Millisecond)
+
+	// Write packets
+	for _, p := range []string{"packet1", "packet2"} {
+		if _, err := v.conn.Write([]byte(p)); err != nil {
+			t.Error(fmt.Errorf("test: writing video packet failed: %w", err))
+		}
+	}
+
+	// Stop recording
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-errs; err != nil {
+		t.Error(fmt.Errorf("test: recording video failed: %w", err))
+	}
+
+	// Check
+	if e, g := "packet1packet2", buf.String(); e != g {
+		t.Errorf("expected %s, got %s", e, g)
+	}
+}
+
+func TestVideoChan(t *testing.T) {
+	// Update defaults
+	s := videoChanSize
+	videoChanSize = 2
+	defer func() { videoChanSize = s }()
+
+	// Set up and start
+	d, _, _, v, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Get channel
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := d.VideoChan(ctx)
+
+	// Handlers are executed in order, therefore once this one has handled a packet, so has the channel's
+	handled := make(chan bool, 3)
+	d.On(VideoPacketEvent, func(interface{}) { handled <- true })
+
+	// Write packets while the consumer is not reading
+	for _, p := range []string{"packet1", "packet2", "packet3"} {
+		if _, err := v.conn.Write([]byte(p)); err != nil {
+			t.Error(fmt.Errorf("test: writing video packet failed: %w", err))
+		}
+	}
+	for i := 0; i < 3; i++ {
+		select {
+		case <-handled:
+		case <-time.After(time.Second):
+			t.Fatal("expected packet to be handled")
+		}
+	}
+
+	// Read packets, the oldest one having been dropped
+	for _, e := range []string{"packet2", "packet3"} {
+		select {
+		case p := <-c:
+			if g := string(p); e != g {
+				t.Errorf("expected %s, got %s", e, g)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected %s", e)
+		}
+	}
+
+	// Channel is closed once the context is done
+	cancel()
+	select {
+	case _, ok := <-c:
+		if ok {
+			t.Error("expected channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Error("expected channel to be closed")
+	}
+}
+
+type mockedDecoder struct{}
+
+func (mockedDecoder) Decode(f VideoFrame) ([]image.Image, error) {
+	return []image.Image{image.NewGray(image.Rect(0, 0, len(f.Data), 1))}, nil
+}
+
+func TestVideoDecoder(t *testing.T) {
+	// Set up and start
+	d, _, _, v, teardown := setupAndStart(t, WithVideoDecoder(mockedDecoder{}))
+	defer teardown()
+
+	// Handle images
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	mi := &sync.Mutex{} // Locks ws
+	var ws []int
+	d.On(VideoImageEvent, VideoImageEventHandler(func(i image.Image) {
+		mi.Lock()
+		ws = append(ws, i.Bounds().Dx())
+		mi.Unlock()
+		wg.Done()
+	}))
+
+	// Write access units, the first one should be dropped since it's not a keyframe
+	kf := bytes.Join([][]byte{nalUnit(nalUnitTypeSPS, 0x1), nalUnit(nalUnitTypePPS, 0x1), nalUnit(nalUnitTypeSliceIDR, 0x80)}, nil)
+	p := nalUnit(nalUnitTypeSlice, 0x80)
+	for _, b := range [][]byte{p, kf, p, kf} {
+		if _, err := v.conn.Write(b); err != nil {
+			t.Error(fmt.Errorf("test: writing video packet failed: %w", err))
+		}
+	}
+
+	// Wait
+	wg.Wait()
+
+	// Check
+	mi.Lock()
+	defer mi.Unlock()
+	if e := []int{len(kf), len(p)}; !reflect.DeepEqual(e, ws) {
+		t.Errorf("expected %+v, got %+v", e, ws)
+	}
+}
+
+func TestVideoDecoderBuffer(t *testing.T) {
+	// Fill buffer
+	vd := newVideoDecoder(mockedDecoder{}, 2)
+	var dropped uint64
+	vd.dropped = &dropped
+	for _, f := range []VideoFrame{{}, {Keyframe: true}, {}} {
+		vd.add(f)
+	}
+	if e, g := 2, len(vd.fs); e != g {
+		t.Errorf("expected %d, got %d", e, g)
+	}
+
+	// Buffer is full, access units should be dropped until next keyframe
+	vd.add(VideoFrame{})
+	if e, g := 0, len(vd.fs); e != g {
+		t.Errorf("expected %d, got %d", e, g)
+	}
+	vd.add(VideoFrame{Keyframe: true})
+	if e, g := 1, len(vd.fs); e != g {
+		t.Errorf("expected %d, got %d", e, g)
+	}
+
+	// Dropped access units should be counted
+	if e, g := uint64(4), dropped; e != g {
+		t.Errorf("expected %d dropped, got %d", e, g)
+	}
+}
+
+func TestFeedVideoFromFile(t *testing.T) {
+	// Update defaults
+	i := videoFeedInterval
+	videoFeedInterval = 10 * time.Millisecond
+	defer func() { videoFeedInterval = i }()
+
+	// Create NAL units, including ones longer than the MTU and exactly as long as the MTU
+	d := New(nil, WithVideoFrames(true))
+	mtu := d.o.videoMTU
+	ns := [][]byte{
+		nalUnit(nalUnitTypeSPS, 0x1),
+		nalUnit(nalUnitTypePPS, 0x1),
+		nalUnit(nalUnitTypeSliceIDR, append([]byte{0x80}, bytes.Repeat([]byte{0x1}, 2*mtu)...)...),
+		nalUnit(nalUnitTypeSlice, 0x80),
+		nalUnit(nalUnitTypeSlice, append([]byte{0x80}, bytes.Repeat([]byte{0x1}, mtu-8)...)...),
+		nalUnit(nalUnitTypeSlice, 0x80),
+	}
+	if l := len(ns[4]); l != mtu {
+		t.Fatalf("expected nal unit of length %d, got %d", mtu, l)
+	}
+
+	// Create file
+	dir, err := ioutil.TempDir("", "astitello")
+	if err != nil {
+		t.Fatal(fmt.Errorf("test: creating temp dir failed: %w", err))
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "video.h264")
+	if err = ioutil.WriteFile(path, bytes.Join(ns, nil), 0600); err != nil {
+		t.Fatal(fmt.Errorf("test: writing file failed: %w", err))
+	}
+
+	// Handle events
+	m := &sync.Mutex{} // Locks fs and ps
+	var fs int
+	var ps [][]byte
+	d.On(VideoFrameEvent, VideoFrameEventHandler(func(VideoFrame) {
+		m.Lock()
+		defer m.Unlock()
+		fs++
+	}))
+	d.On(VideoPacketEvent, VideoPacketEventHandler(func(p []byte) {
+		m.Lock()
+		defer m.Unlock()
+		ps = append(ps, p)
+	}))
+
+	// Feed
+	n := time.Now()
+	if err = d.FeedVideoFromFile(context.Background(), path); err != nil {
+		t.Fatal(fmt.Errorf("test: feeding video failed: %w", err))
+	}
+	if e, g := 3*videoFeedInterval, time.Since(n); g < e {
+		t.Errorf("expected feeding to take at least %s, took %s", e, g)
+	}
+
+	// Wait for events to be processed
+	for n := time.Now(); ; time.Sleep(time.Millisecond) {
+		m.Lock()
+		l, lf := len(ps), fs
+		m.Unlock()
+		if l >= len(ns) && lf >= 3 {
+			break
+		} else if time.Since(n) > time.Second {
+			t.Fatalf("expected %d packets and 3 frames, got %d packets and %d frames", len(ns), l, lf)
+		}
+	}
+
+	// Check
+	m.Lock()
+	defer m.Unlock()
+	if !reflect.DeepEqual(ns, ps) {
+		t.Errorf("expected %d packets, got %d", len(ns), len(ps))
+	}
+	if e := 3; fs != e {
+		t.Errorf("expected %d frames, got %d", e, fs)
+	}
+	if e, g := uint64(0), d.VideoStats().Dropped; e != g {
+		t.Errorf("expected %d dropped, got %d", e, g)
+	}
+}
@@ -0,0 +1,134 @@
+package astitello
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Video drop policies
+const (
+	VideoDropPolicyNewest VideoDropPolicy = "newest"
+	VideoDropPolicyOldest VideoDropPolicy = "oldest"
+)
+
+// VideoDropPolicy represents which video events are dropped when the video buffer is full
+type VideoDropPolicy string
+
+type videoEvent struct {
+	name    string
+	payload interface{}
+}
+
+// videoBuffer is a bounded ring buffer of video events sitting between the video goroutine and event
+// handlers, so that slow handlers don't make memory grow indefinitely
+type videoBuffer struct {
+	c       *sync.Cond
+	dropped *uint64 // Incremented atomically when events are dropped, if not nil
+	es      []videoEvent
+	head    int
+	n       int
+	p       VideoDropPolicy
+}
+
+func newVideoBuffer(size int, p VideoDropPolicy) *videoBuffer {
+	return &videoBuffer{
+		c:  sync.NewCond(&sync.Mutex{}),
+		es: make([]videoEvent, size),
+		p:  p,
+	}
+}
+
+func (vb *videoBuffer) push(e videoEvent) {
+	// Lock
+	vb.c.L.Lock()
+	defer vb.c.L.Unlock()
+
+	// Buffer is full
+	if vb.n == len(vb.es) {
+		// Update stats
+		if vb.dropped != nil {
+			atomic.AddUint64(vb.dropped, 1)
+		}
+
+		// Drop
+		if vb.p == VideoDropPolicyNewest {
+			return
+		}
+		vb.es[vb.head] = videoEvent{}
+		vb.head = (vb.head + 1) % len(vb.es)
+		vb.n--
+	}
+
+	// Append
+	vb.es[(vb.head+vb.n)%len(vb.es)] = e
+	vb.n++
+
+	// Signal
+	vb.c.Signal()
+}
+
+// pop blocks until an event is available or the context is done
+func (vb *videoBuffer) pop(ctx context.Context) (e videoEvent, ok bool) {
+	// Lock
+	vb.c.L.Lock()
+	defer vb.c.L.Unlock()
+
+	// Wait for an event
+	for vb.n == 0 && ctx.Err() == nil {
+		vb.c.Wait()
+	}
+
+	// Check context
+	if ctx.Err() != nil {
+		return
+	}
+
+	// Shift
+	e, ok = vb.es[vb.head], true
+	vb.es[vb.head] = videoEvent{}
+	vb.head = (vb.head + 1) % len(vb.es)
+	vb.n--
+	return
+}
+
+func (vb *videoBuffer) start(ctx context.Context, e *eventer) {
+	// Handle context
+	go func() {
+		// Wait for context to be done
+		<-ctx.Done()
+
+		// Signal
+		vb.c.L.Lock()
+		vb.c.Signal()
+		vb.c.L.Unlock()
+	}()
+
+	// Loop
+	for {
+		// Pop
+		ve, ok := vb.pop(ctx)
+		if !ok {
+			return
+		}
+
+		// Dispatch
+		e.Dispatch(ve.name, ve.payload)
+
+		// Wait for the event to be processed so that events pile up in the buffer instead of the eventer
+		select {
+		case <-e.processed():
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// dispatchVideo dispatches a video event through the video buffer if any
+func (d *Drone) dispatchVideo(name string, payload interface{}) {
+	if d.vb != nil {
+		d.vb.push(videoEvent{name: name, payload: payload})
+		return
+	}
+	d.e.Dispatch(name, payload)
+}
@@ -0,0 +1,111 @@
+package astitello
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestVideoBuffer(t *testing.T) {
+	for _, v := range []struct {
+		e []interface{}
+		p VideoDropPolicy
+	}{
+		{e: []interface{}{3, 4}, p: VideoDropPolicyOldest},
+		{e: []interface{}{1, 2}, p: VideoDropPolicyNewest},
+	} {
+		// Push
+		var dropped uint64
+		vb := newVideoBuffer(2, v.p)
+		vb.dropped = &dropped
+		for i := 1; i <= 4; i++ {
+			vb.push(videoEvent{payload: i})
+		}
+
+		// Pop
+		var g []interface{}
+		for vb.n > 0 {
+			e, _ := vb.pop(context.Background())
+			g = append(g, e.payload)
+		}
+		if !reflect.DeepEqual(v.e, g) {
+			t.Errorf("%s: expected %+v, got %+v", v.p, v.e, g)
+		}
+		if e, g := uint64(2), dropped; e != g {
+			t.Errorf("%s: expected %d dropped, got %d", v.p, e, g)
+		}
+	}
+}
+
+func TestVideoBufferBlockedConsumer(t *testing.T) {
+	for _, v := range []struct {
+		e []byte
+		p VideoDropPolicy
+	}{
+		{e: []byte{1, 4, 5}, p: VideoDropPolicyOldest},
+		{e: []byte{1, 2, 3}, p: VideoDropPolicyNewest},
+	} {
+		t.Run(string(v.p), func(t *testing.T) {
+			// Set up and start
+			d, _, _, vd, teardown := setupAndStart(t, WithVideoBufferSize(2), WithVideoDropPolicy(v.p))
+			defer teardown()
+
+			// Handle video packets, the first one blocking until unblocked
+			mp := &sync.Mutex{} // Locks ps
+			var ps []byte
+			blocked, unblock := make(chan bool), make(chan bool)
+			d.On(VideoPacketEvent, VideoPacketEventHandler(func(p []byte) {
+				mp.Lock()
+				ps = append(ps, p[len(p)-1])
+				first := len(ps) == 1
+				mp.Unlock()
+				if first {
+					blocked <- true
+					<-unblock
+				}
+			}))
+
+			// Write packets
+			write := func(i byte) {
+				if _, err := vd.conn.Write(append(append([]byte{}, videoStartCode...), i)); err != nil {
+					t.Fatal(fmt.Errorf("test: writing video datagram failed: %w", err))
+				}
+			}
+			write(1)
+			select {
+			case <-blocked:
+			case <-time.After(time.Second):
+				t.Fatal("expected consumer to be blocked")
+			}
+			for i := byte(2); i <= 5; i++ {
+				write(i)
+			}
+
+			// Wait for the reader to drain the socket while the consumer is blocked
+			for n := time.Now(); d.VideoStats().Packets < 5; time.Sleep(time.Millisecond) {
+				if time.Since(n) > time.Second {
+					t.Fatalf("expected 5 packets, got %d", d.VideoStats().Packets)
+				}
+			}
+			close(unblock)
+
+			// Wait for the remaining packets
+			for n := time.Now(); ; time.Sleep(time.Millisecond) {
+				mp.Lock()
+				g := append([]byte{}, ps...)
+				mp.Unlock()
+				if reflect.DeepEqual(v.e, g) {
+					break
+				} else if time.Since(n) > time.Second {
+					t.Fatalf("expected %+v, got %+v", v.e, g)
+				}
+			}
+			if e, g := uint64(2), d.VideoStats().BufferDropped; e != g {
+				t.Errorf("expected %d dropped, got %d", e, g)
+			}
+		})
+	}
+}
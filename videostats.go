@@ -0,0 +1,82 @@
+package astitello
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/asticode/go-astikit"
+)
+
+// Interval at which the VideoStats event is dispatched
+var videoStatsInterval = time.Second
+
+// VideoStats represents video stream statistics since the drone was started
+// Comparing Dropped and DecoderDropped helps telling whether choppy video comes from the network or from a
+// slow decoder.
+type VideoStats struct {
+	BufferDropped  uint64 `json:"buffer_dropped"`  // The number of events dropped because the video buffer was full, if WithVideoBufferSize is used
+	Bytes          uint64 `json:"bytes"`           // The number of bytes received
+	Datagrams      uint64 `json:"datagrams"`       // The number of datagrams received
+	DecoderDropped uint64 `json:"decoder_dropped"` // The number of access units dropped by the decoder because it was too slow or was waiting for a keyframe
+	Dropped        uint64 `json:"dropped"`         // The estimated number of packets whose first datagram was lost
+	Frames         uint64 `json:"frames"`          // The number of access units parsed, if WithVideoFrames or WithVideoDecoder is used
+	Packets        uint64 `json:"packets"`         // The number of packets reassembled from datagrams
+}
+
+// VideoStatsEventHandler returns the proper EventHandler for the VideoStats event
+func VideoStatsEventHandler(f func(s VideoStats)) astikit.EventerHandler {
+	return func(payload interface{}) {
+		f(payload.(VideoStats))
+	}
+}
+
+// VideoStats returns video stream statistics
+func (d *Drone) VideoStats() VideoStats {
+	return VideoStats{
+		BufferDropped:  atomic.LoadUint64(&d.vs.BufferDropped),
+		Bytes:          atomic.LoadUint64(&d.vs.Bytes),
+		Datagrams:      atomic.LoadUint64(&d.vs.Datagrams),
+		DecoderDropped: atomic.LoadUint64(&d.vs.DecoderDropped),
+		Dropped:        atomic.LoadUint64(&d.vs.Dropped),
+		Frames:         atomic.LoadUint64(&d.vs.Frames),
+		Packets:        atomic.LoadUint64(&d.vs.Packets),
+	}
+}
+
+func (d *Drone) resetVideoStats() {
+	for _, v := range []*uint64{
+		&d.vs.BufferDropped,
+		&d.vs.Bytes,
+		&d.vs.Datagrams,
+		&d.vs.DecoderDropped,
+		&d.vs.Dropped,
+		&d.vs.Frames,
+		&d.vs.Packets,
+	} {
+		atomic.StoreUint64(v, 0)
+	}
+}
+
+func (d *Drone) startVideoStats() {
+	d.wg.Add(1)
+	go d.dispatchVideoStats()
+}
+
+func (d *Drone) dispatchVideoStats() {
+	// Make sure to signal the goroutine is done
+	defer d.wg.Done()
+
+	// Create ticker
+	t := time.NewTicker(videoStatsInterval)
+	defer t.Stop()
+
+	// Loop
+	for {
+		select {
+		case <-t.C:
+			d.e.Dispatch(VideoStatsEvent, d.VideoStats())
+		case <-d.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package astitello
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestVideoStats(t *testing.T) {
+	// Update defaults
+	i := videoStatsInterval
+	videoStatsInterval = 5 * time.Millisecond
+	defer func() { videoStatsInterval = i }()
+
+	// Set up and start
+	d, _, _, v, teardown := setupAndStart(t)
+	defer teardown()
+
+	// Handle events
+	stats := make(chan VideoStats, 1)
+	d.On(VideoStatsEvent, VideoStatsEventHandler(func(s VideoStats) {
+		select {
+		case stats <- s:
+		default:
+		}
+	}))
+
+	// Write complete packets, and packets whose first datagram has been lost, which are counted once each
+	p := append(append([]byte{}, videoStartCode...), 1, 2)
+	for _, b := range [][]byte{p, []byte("abc"), []byte("def"), p, []byte("ghi")} {
+		if _, err := v.conn.Write(b); err != nil {
+			t.Fatal(fmt.Errorf("test: writing video datagram failed: %w", err))
+		}
+	}
+
+	// Wait for stats
+	e := VideoStats{Bytes: 21, Datagrams: 5, Dropped: 2, Packets: 5}
+	for n := time.Now(); ; {
+		select {
+		case s := <-stats:
+			if s == e {
+				return
+			} else if time.Since(n) > time.Second {
+				t.Fatalf("expected %+v, got %+v", e, s)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected video stats event")
+		}
+	}
+}
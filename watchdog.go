@@ -0,0 +1,99 @@
+package astitello
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/asticode/go-astikit"
+)
+
+// Telemetry lost actions
+const (
+	TelemetryLostActionEmergency TelemetryLostAction = "emergency"
+	TelemetryLostActionLand      TelemetryLostAction = "land"
+	TelemetryLostActionNone      TelemetryLostAction = ""
+)
+
+// Minimum interval at which the telemetry watchdog checks states, so that tiny timeouts don't make it spin or
+// make the ticker panic
+var telemetryWatchdogMinInterval = 10 * time.Millisecond
+
+// TelemetryLostAction represents the cmd sent when telemetry is lost while airborne
+type TelemetryLostAction string
+
+// TelemetryLostEventHandler returns the proper EventHandler for the TelemetryLost event
+// The duration is the time elapsed since the last state was received
+func TelemetryLostEventHandler(f func(since time.Duration)) astikit.EventerHandler {
+	return func(payload interface{}) {
+		f(payload.(time.Duration))
+	}
+}
+
+func (d *Drone) startTelemetryWatchdog() {
+	d.wg.Add(1)
+	go d.watchTelemetry()
+}
+
+func (d *Drone) watchTelemetry() {
+	// Make sure to signal the goroutine is done
+	defer d.wg.Done()
+
+	// States received during previous sessions don't count
+	start := time.Now()
+
+	// Create ticker
+	t := time.NewTicker(telemetryWatchdogInterval(d.o.telemetryTimeout))
+	defer t.Stop()
+
+	// Loop
+	var lost bool
+	for {
+		select {
+		case <-t.C:
+		case <-d.ctx.Done():
+			return
+		}
+
+		// Get state info
+		d.ms.Lock()
+		airborne := d.fs == FlightStateAirborne
+		last := d.stateAt
+		d.ms.Unlock()
+		if last.Before(start) {
+			last = start
+		}
+		since := time.Since(last)
+
+		// Telemetry is fine or was already reported as lost
+		if since <= d.o.telemetryTimeout || !airborne {
+			lost = false
+			continue
+		} else if lost {
+			continue
+		}
+		lost = true
+
+		// Log and dispatch
+		d.l.Errorf("astitello: no state received for %s while airborne", since)
+		d.e.Dispatch(TelemetryLostEvent, since)
+
+		// Execute action
+		switch d.o.telemetryLostAction {
+		case TelemetryLostActionEmergency:
+			if err := d.Emergency(); err != nil {
+				d.l.Error(fmt.Errorf("astitello: emergency after telemetry loss failed: %w", err))
+			}
+		case TelemetryLostActionLand:
+			if err := d.Land(); err != nil {
+				d.l.Error(fmt.Errorf("astitello: landing after telemetry loss failed: %w", err))
+			}
+		}
+	}
+}
+
+func telemetryWatchdogInterval(timeout time.Duration) time.Duration {
+	if i := timeout / 4; i > telemetryWatchdogMinInterval {
+		return i
+	}
+	return telemetryWatchdogMinInterval
+}
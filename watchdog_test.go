@@ -0,0 +1,63 @@
+package astitello
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTelemetryWatchdog(t *testing.T) {
+	// Set up and start
+	d, c, s, _, teardown := setupAndStart(t, WithTelemetryTimeout(50*time.Millisecond), WithTelemetryLostAction(TelemetryLostActionLand))
+	defer teardown()
+
+	// Handle events
+	lost := make(chan time.Duration, 1)
+	d.On(TelemetryLostEvent, TelemetryLostEventHandler(func(since time.Duration) { lost <- since }))
+
+	// Take off
+	if err := d.TakeOff(); err != nil {
+		t.Fatal(fmt.Errorf("test: taking off failed: %w", err))
+	}
+
+	// Write state and stop the state dialer
+	if _, err := s.conn.Write([]byte(strState)); err != nil {
+		t.Fatal(fmt.Errorf("test: writing state failed: %w", err))
+	}
+	s.close()
+
+	// Wait for event
+	select {
+	case since := <-lost:
+		if since < 50*time.Millisecond {
+			t.Errorf("expected at least 50ms, got %s", since)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected telemetry lost event")
+	}
+
+	// Drone should land
+	for i := 0; i < 100; i++ {
+		cmds := c.received()
+		if cmds[len(cmds)-1] == "land" {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Error("expected land cmd")
+}
+
+func TestTelemetryWatchdogInterval(t *testing.T) {
+	for _, v := range []struct {
+		e       time.Duration
+		timeout time.Duration
+	}{
+		{e: 10 * time.Millisecond, timeout: time.Nanosecond},
+		{e: 10 * time.Millisecond, timeout: 20 * time.Millisecond},
+		{e: 250 * time.Millisecond, timeout: time.Second},
+	} {
+		if g := telemetryWatchdogInterval(v.timeout); g != v.e {
+			t.Errorf("%s: expected %s, got %s", v.timeout, v.e, g)
+		}
+	}
+}